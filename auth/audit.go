@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"log"
+	"time"
+
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+
+	"github.com/google/uuid"
+)
+
+// Audit appends an entry to the audit log. When the entry names a resource
+// but no organization, the organization is found by following the resource's
+// parent links, so it must be called before those links are removed. Failures
+// are logged rather than returned so auditing never blocks the audited action.
+func (s *Service) Audit(entry models.AuditEntry) {
+	entry.ID = uuid.New().String()
+	entry.CreatedAt = time.Now()
+	if entry.ActorID == "" {
+		entry.ActorID = s.actorID
+	}
+	if entry.OrganizationID == "" && entry.ResourceID != "" {
+		entry.OrganizationID = s.organizationOf(entry.ResourceID)
+	}
+
+	if err := database.DB.Create(&entry).Error; err != nil {
+		log.Printf("Failed to write audit entry %s for %s: %v\n", entry.Action, entry.ResourceID, err)
+	}
+}
+
+// organizationOf walks up the g2 links from a resource to the organization at
+// the root of its hierarchy, returning "" if the root is not an organization.
+func (s *Service) organizationOf(obj string) string {
+	seen := map[string]bool{}
+	for !seen[obj] {
+		seen[obj] = true
+		links, err := s.enforcer.GetFilteredNamedGroupingPolicy("g2", 0, obj)
+		if err != nil || len(links) == 0 {
+			break
+		}
+		obj = links[0][1]
+	}
+
+	var count int64
+	if err := database.DB.Model(&models.Organization{}).Where("id = ?", obj).Count(&count).Error; err != nil || count == 0 {
+		return ""
+	}
+	return obj
+}
@@ -1,14 +1,17 @@
-
 package auth
 
 import (
+	"net/http"
+
 	"kanban-app/api/database"
+	"kanban-app/api/models"
 
 	"github.com/casbin/casbin/v2"
 )
 
 type Service struct {
 	enforcer *casbin.Enforcer
+	actorID  string
 }
 
 func NewAuthorizationService() *Service {
@@ -17,14 +20,104 @@ func NewAuthorizationService() *Service {
 	}
 }
 
+// WithActor returns a copy of the service that attributes the policy changes
+// it makes to actorID in the audit log.
+func (s *Service) WithActor(actorID string) *Service {
+	return &Service{enforcer: s.enforcer, actorID: actorID}
+}
+
+// ActionForMethod maps an HTTP method to the action it requires.
+func ActionForMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return models.ActionRead
+	case http.MethodDelete:
+		return models.ActionDelete
+	default:
+		return models.ActionWrite
+	}
+}
+
 func (s *Service) Enforce(sub, obj, act string) (bool, error) {
 	return s.enforcer.Enforce(sub, obj, act)
 }
 
 func (s *Service) AddPolicy(sub, obj, act string) (bool, error) {
-	return s.enforcer.AddPolicy(sub, obj, act)
+	added, err := s.enforcer.AddPolicy(sub, obj, act)
+	if added {
+		s.auditPolicy(models.AuditPolicyAdded, sub, obj, act)
+	}
+	return added, err
 }
 
 func (s *Service) RemovePolicy(sub, obj, act string) (bool, error) {
-	return s.enforcer.RemovePolicy(sub, obj, act)
+	removed, err := s.enforcer.RemovePolicy(sub, obj, act)
+	if removed {
+		s.auditPolicy(models.AuditPolicyRemoved, sub, obj, act)
+	}
+	return removed, err
+}
+
+// GetResourcePolicies returns the policies granted directly on obj.
+func (s *Service) GetResourcePolicies(obj string) ([][]string, error) {
+	return s.enforcer.GetFilteredPolicy(1, obj)
+}
+
+// RemoveSubjectPolicies removes every policy granted to sub directly on obj.
+func (s *Service) RemoveSubjectPolicies(sub, obj string) (bool, error) {
+	policies, err := s.enforcer.GetFilteredPolicy(0, sub, obj)
+	if err != nil {
+		return false, err
+	}
+	removed, err := s.enforcer.RemoveFilteredPolicy(0, sub, obj)
+	if removed {
+		for _, policy := range policies {
+			s.auditPolicy(models.AuditPolicyRemoved, policy[0], policy[1], policy[2])
+		}
+	}
+	return removed, err
+}
+
+// AddResourceParent links a resource to its parent so that any policy granted
+// on the parent (or one of its ancestors) also applies to the resource.
+func (s *Service) AddResourceParent(child, parent string) (bool, error) {
+	return s.enforcer.AddNamedGroupingPolicy("g2", child, parent)
+}
+
+func (s *Service) RemoveResourceParent(child, parent string) (bool, error) {
+	return s.enforcer.RemoveNamedGroupingPolicy("g2", child, parent)
+}
+
+// RemoveResourcePolicies removes every policy granted on obj and the link to
+// its parent, returning how many rules were removed. It is used once the
+// resource itself has been deleted.
+func (s *Service) RemoveResourcePolicies(obj string) (int, error) {
+	policies, err := s.enforcer.GetFilteredPolicy(1, obj)
+	if err != nil {
+		return 0, err
+	}
+	links, err := s.enforcer.GetFilteredNamedGroupingPolicy("g2", 0, obj)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(policies) > 0 {
+		if _, err := s.enforcer.RemoveFilteredPolicy(1, obj); err != nil {
+			return 0, err
+		}
+	}
+	if len(links) > 0 {
+		if _, err := s.enforcer.RemoveFilteredNamedGroupingPolicy("g2", 0, obj); err != nil {
+			return len(policies), err
+		}
+	}
+	return len(policies) + len(links), nil
+}
+
+func (s *Service) auditPolicy(action, sub, obj, act string) {
+	s.Audit(models.AuditEntry{
+		Action:     action,
+		ResourceID: obj,
+		Details:    map[string]any{"subject": sub, "role": act},
+	})
 }
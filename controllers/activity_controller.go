@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"net/http"
+
+	"kanban-app/api/models"
+	"kanban-app/api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var activityService *services.ActivityService
+
+func init() {
+	activityService = services.NewActivityService()
+}
+
+// GetCardActivity handles retrieving the history of a card.
+// @Summary Get card activity
+// @Description Retrieves a page of the changes made to a card, newest first. Each entry names the actor and holds the changed fields before and after. Requires read access to the card.
+// @Tags Activity
+// @Security ApiKeyAuth
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Entries per page (default 50, max 200)"
+// @Success 200 {object} models.ActivityPage "Page of activity"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/activity [get]
+func GetCardActivity(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	page, err := activityService.GetCardActivity(c.Param("cardID"), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve card activity: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetBoardActivity handles retrieving the history of every card on a board.
+// @Summary Get board activity
+// @Description Retrieves a page of the changes made to the board's cards, newest first, including cards that have since been deleted. Requires read access to the board.
+// @Tags Activity
+// @Security ApiKeyAuth
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Entries per page (default 50, max 200)"
+// @Success 200 {object} models.ActivityPage "Page of activity"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/activity [get]
+func GetBoardActivity(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	page, err := activityService.GetBoardActivity(c.Param("boardID"), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve board activity: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -3,6 +3,7 @@ package controllers
 
 import (
 	"net/http"
+	"strings"
 
 	"kanban-app/api/models"
 	"kanban-app/api/services"
@@ -26,6 +27,7 @@ func init() {
 // @Param cardID path string true "Card ID"
 // @Param attachment body models.CreateAttachmentRequest true "Attachment creation details"
 // @Success 201 {object} models.Attachment "Attachment created successfully"
+// @Header 201 {string} ETag "Version of the attachment"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
@@ -33,6 +35,7 @@ func init() {
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /cards/{cardID}/attachments [post]
 func CreateAttachment(c *gin.Context) {
+	userID, _ := c.Get("userID")
 	cardID := c.Param("cardID")
 
 	var req models.CreateAttachmentRequest
@@ -41,12 +44,13 @@ func CreateAttachment(c *gin.Context) {
 		return
 	}
 
-	attachment, err := attachmentService.CreateAttachment(cardID, req.FileName, req.FileURL, req.FileType)
+	attachment, err := attachmentService.CreateAttachment(cardID, req.FileName, req.FileURL, req.FileType, userID.(string))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to create attachment: " + err.Error()})
 		return
 	}
 
+	setETag(c, attachment.Version)
 	c.JSON(http.StatusCreated, attachment)
 }
 
@@ -57,16 +61,29 @@ func CreateAttachment(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Param cardID path string true "Card ID"
 // @Param attachmentID path string true "Attachment ID"
+// @Param If-Match header string true "ETag of the version being deleted, or *"
 // @Success 204 "No Content"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the attachment has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /cards/{cardID}/attachments/{attachmentID} [delete]
 func DeleteAttachment(c *gin.Context) {
+	userID, _ := c.Get("userID")
 	attachmentID := c.Param("attachmentID")
 
-	if err := attachmentService.DeleteAttachment(attachmentID); err != nil {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := attachmentService.DeleteAttachment(attachmentID, version, userID.(string)); err != nil {
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to delete attachment: " + err.Error()})
 		return
 	}
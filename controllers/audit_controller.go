@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"kanban-app/api/models"
+	"kanban-app/api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var auditService *services.AuditService
+
+func init() {
+	auditService = services.NewAuditService()
+}
+
+// GetAuditLog handles retrieving an organization's audit log.
+// @Summary Get the audit log
+// @Description Retrieves a page of the organization's audit log, newest first: policy changes, project and organization deletions, denied requests, and the logins and registrations of its members. Requires the admin role.
+// @Tags Audit
+// @Security ApiKeyAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param actor_id query string false "Only entries by this user"
+// @Param action query string false "Only entries with this action, e.g. policy.added"
+// @Param from query string false "Only entries at or after this RFC 3339 time"
+// @Param to query string false "Only entries before this RFC 3339 time"
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Entries per page (default 50, max 200)"
+// @Success 200 {object} models.AuditPage "Page of audit entries"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/audit [get]
+func GetAuditLog(c *gin.Context) {
+	var query models.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	page, err := auditService.GetAuditLog(c.Param("orgID"), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve audit log: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ExportAuditLog handles exporting an organization's audit log as NDJSON.
+// @Summary Export the audit log
+// @Description Streams every matching audit entry, oldest first, as newline-delimited JSON. Accepts the same filters as the audit log. Requires the admin role.
+// @Tags Audit
+// @Security ApiKeyAuth
+// @Produce application/x-ndjson
+// @Param orgID path string true "Organization ID"
+// @Param actor_id query string false "Only entries by this user"
+// @Param action query string false "Only entries with this action, e.g. policy.added"
+// @Param from query string false "Only entries at or after this RFC 3339 time"
+// @Param to query string false "Only entries before this RFC 3339 time"
+// @Success 200 {object} models.AuditEntry "One audit entry per line"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/audit/export [get]
+func ExportAuditLog(c *gin.Context) {
+	orgID := c.Param("orgID")
+
+	var query models.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// Headers are only sent with the first entry so that a failing query can
+	// still be reported as an error.
+	started := false
+	start := func() {
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Header("Content-Disposition", `attachment; filename="audit-`+orgID+`.ndjson"`)
+		c.Status(http.StatusOK)
+		started = true
+	}
+	encoder := json.NewEncoder(c.Writer)
+
+	err := auditService.ExportAuditLog(orgID, query, func(entry *models.AuditEntry) error {
+		if !started {
+			start()
+		}
+		return encoder.Encode(entry)
+	})
+	switch {
+	case err != nil && !started:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to export audit log: " + err.Error()})
+	case err != nil:
+		log.Printf("Audit export for organization %s stopped early: %v\n", orgID, err)
+	case !started:
+		start()
+	}
+}
@@ -41,6 +41,7 @@ func RegisterUser(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to register user: " + err.Error()})
 		return
 	}
+	auditService.RecordRegistration(user, c.ClientIP())
 
 	user.Password = "" // TODO check if password wont show by default due to json binding in struct
 	c.JSON(http.StatusCreated, user)
@@ -67,6 +68,7 @@ func LoginUser(c *gin.Context) {
 	}
 
 	user, err := userService.AuthenticateUser(req)
+	auditService.RecordLogin(req.Email, c.ClientIP(), user, err)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Message: err.Error()})
 		return
@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"kanban-app/api/models"
+	"kanban-app/api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var automationService *services.AutomationService
+
+func init() {
+	automationService = services.NewAutomationService()
+}
+
+// CreateAutomation handles adding an automation to a board.
+// @Summary Create an automation
+// @Description Adds a rule that runs actions on a card of the board when a trigger fires and the card meets the conditions. Triggers are card_moved_into_list (list_id), label_added (label_id) and due_date_passed, which is checked every minute. Conditions require the card to carry every label in label_ids and be assigned to every user in assignee_ids. Actions, applied in order in one transaction, are move_to_list (list_id, position), add_label (label_id), set_due_date (due_in_days from when it runs, or null to clear it) and add_comment (text); lists and labels must be on the board. Actions run on behalf of the user who created the automation, needing their write access, and may fire other automations, up to 5 in a row and once per automation and card in a chain. Archived cards don't fire automations. Requires write access to the board.
+// @Tags Automations
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Param automation body models.CreateAutomationRequest true "Automation details"
+// @Success 201 {object} models.Automation "Automation created successfully"
+// @Header 201 {string} ETag "Version of the automation"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/automations [post]
+func CreateAutomation(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req models.CreateAutomationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	automation, err := automationService.CreateAutomation(c.Param("boardID"), userID.(string), req)
+	if err != nil {
+		handleAutomationError(c, "Failed to create automation: ", err)
+		return
+	}
+
+	setETag(c, automation.Version)
+	c.JSON(http.StatusCreated, automation)
+}
+
+// GetAutomations handles listing the automations of a board.
+// @Summary Get board automations
+// @Description Retrieves the automations of the board in the order they run. Requires read access to the board.
+// @Tags Automations
+// @Security ApiKeyAuth
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Success 200 {array} models.Automation "List of automations"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/automations [get]
+func GetAutomations(c *gin.Context) {
+	automations, err := automationService.GetAutomations(c.Param("boardID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve automations: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, automations)
+}
+
+// GetAutomationByID handles retrieving an automation of a board.
+// @Summary Get automation by ID
+// @Description Retrieves an automation of the board. Requires read access to the board.
+// @Tags Automations
+// @Security ApiKeyAuth
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Param automationID path string true "Automation ID"
+// @Success 200 {object} models.Automation "Automation details"
+// @Header 200 {string} ETag "Version of the automation"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/automations/{automationID} [get]
+func GetAutomationByID(c *gin.Context) {
+	automation, err := automationService.GetAutomationByID(c.Param("boardID"), c.Param("automationID"))
+	if err != nil {
+		handleAutomationError(c, "Failed to retrieve automation: ", err)
+		return
+	}
+
+	setETag(c, automation.Version)
+	c.JSON(http.StatusOK, automation)
+}
+
+// UpdateAutomation handles changing an automation of a board.
+// @Summary Update an automation
+// @Description Changes only the fields present in the body; a trigger, conditions or actions given replace the current ones. Requires write access to the board.
+// @Tags Automations
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Param automationID path string true "Automation ID"
+// @Param automation body models.UpdateAutomationRequest true "Automation update details"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.Automation "Automation updated successfully"
+// @Header 200 {string} ETag "Version of the automation"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the automation has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/automations/{automationID} [put]
+func UpdateAutomation(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateAutomationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	automation, err := automationService.UpdateAutomation(c.Param("boardID"), c.Param("automationID"), version, req)
+	if err != nil {
+		handleAutomationError(c, "Failed to update automation: ", err)
+		return
+	}
+
+	setETag(c, automation.Version)
+	c.JSON(http.StatusOK, automation)
+}
+
+// DeleteAutomation handles deleting an automation of a board.
+// @Summary Delete an automation
+// @Description Deletes an automation together with its executions. Requires write access to the board.
+// @Tags Automations
+// @Security ApiKeyAuth
+// @Param boardID path string true "Board ID"
+// @Param automationID path string true "Automation ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the automation has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/automations/{automationID} [delete]
+func DeleteAutomation(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := automationService.DeleteAutomation(c.Param("boardID"), c.Param("automationID"), version); err != nil {
+		handleAutomationError(c, "Failed to delete automation: ", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetAutomationExecutions handles retrieving the executions of an automation.
+// @Summary Get automation executions
+// @Description Retrieves a page of the automation's executions from the last 30 days, newest first: the card and trigger, how deep in a chain of automations it ran, the actions applied, and whether it succeeded, failed with an error, or was skipped by the loop guard. Requires read access to the board.
+// @Tags Automations
+// @Security ApiKeyAuth
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Param automationID path string true "Automation ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Executions per page (default 50, max 200)"
+// @Success 200 {object} models.AutomationExecutionPage "Page of executions"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/automations/{automationID}/executions [get]
+func GetAutomationExecutions(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	page, err := automationService.GetExecutions(c.Param("boardID"), c.Param("automationID"), query)
+	if err != nil {
+		handleAutomationError(c, "Failed to retrieve executions: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func handleAutomationError(c *gin.Context, prefix string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid automation"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "version mismatch"):
+		c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: prefix + err.Error()})
+	}
+}
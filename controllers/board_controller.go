@@ -18,7 +18,7 @@ func init() {
 
 // CreateBoard handles creating a new board within a project.
 // @Summary Create a new board
-// @Description Creates a new board within a specified project. User must own the project.
+// @Description Creates a new board within a specified project. Requires write access to the project.
 // @Tags Boards
 // @Security ApiKeyAuth
 // @Accept json
@@ -27,6 +27,7 @@ func init() {
 // @Param projectID path string true "Project ID"
 // @Param board body models.CreateBoardRequest true "Board creation details"
 // @Success 201 {object} models.Board "Board created successfully"
+// @Header 201 {string} ETag "Version of the board"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
@@ -54,12 +55,13 @@ func CreateBoard(c *gin.Context) {
 		return
 	}
 
+	setETag(c, board.Version)
 	c.JSON(http.StatusCreated, board)
 }
 
 // GetBoards handles retrieving all boards for a specific project.
 // @Summary Get all boards in a project
-// @Description Retrieves all boards within a specified project. User must own the project.
+// @Description Retrieves all boards within a specified project. Requires read access to the project.
 // @Tags Boards
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
@@ -85,7 +87,7 @@ func GetBoards(c *gin.Context) {
 
 // GetBoardByID handles retrieving a specific board within a project.
 // @Summary Get board by ID
-// @Description Retrieves a specific board by its ID within a specified project. User must own the project.
+// @Description Retrieves a specific board by its ID within a specified project. Requires read access to the project.
 // @Tags Boards
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
@@ -93,6 +95,7 @@ func GetBoards(c *gin.Context) {
 // @Param boardID path string true "Board ID"
 // @Produce json
 // @Success 200 {object} models.Board "Board details"
+// @Header 200 {string} ETag "Version of the board"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
@@ -111,12 +114,13 @@ func GetBoardByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, board.Version)
 	c.JSON(http.StatusOK, board)
 }
 
 // UpdateBoard handles updating an existing board within a project.
 // @Summary Update a board
-// @Description Updates a specific board by its ID within a specified project. User must own the project.
+// @Description Updates a specific board by its ID within a specified project. The wip_policy decides what happens to a card created in or moved into a list at its WIP limit: strict, the default, refuses it, and warn lets it in and flags the response. Requires write access to the project.
 // @Tags Boards
 // @Security ApiKeyAuth
 // @Accept json
@@ -125,83 +129,123 @@ func GetBoardByID(c *gin.Context) {
 // @Param projectID path string true "Project ID"
 // @Param boardID path string true "Board ID"
 // @Param board body models.UpdateBoardRequest true "Board update details"
+// @Param If-Match header string true "ETag of the version being changed, or *"
 // @Success 200 {object} models.Board "Board updated successfully"
+// @Header 200 {string} ETag "Version of the board"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
 // @Failure 409 {object} models.ErrorResponse "Conflict"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the board has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID} [put]
 func UpdateBoard(c *gin.Context) {
 	boardID := c.Param("boardID")
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req models.UpdateBoardRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	updatedBoard, err := boardService.UpdateBoard(boardID, req)
+	updatedBoard, err := boardService.UpdateBoard(boardID, version, req)
 	if err != nil {
+		if strings.Contains(err.Error(), "board not found") {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		if strings.Contains(err.Error(), "board name already exists") {
 			c.JSON(http.StatusConflict, models.ErrorResponse{Message: err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to update board: " + err.Error()})
 		return
 	}
 
+	setETag(c, updatedBoard.Version)
 	c.JSON(http.StatusOK, updatedBoard)
 }
 
 // DeleteBoard handles deleting a board within a project.
 // @Summary Delete a board
-// @Description Deletes a specific board by its ID within a specified project. User must own the project.
+// @Description Deletes a specific board by its ID within a specified project. Requires delete access to the project. The board is moved to the trash and can be restored until the retention period expires.
 // @Tags Boards
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
 // @Param projectID path string true "Project ID"
 // @Param boardID path string true "Board ID"
-// @Success 204 "No Content"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.DeletionSummary "Counts of everything moved to the trash"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the board has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID} [delete]
 func DeleteBoard(c *gin.Context) {
 	boardID := c.Param("boardID")
 
-	err := boardService.DeleteBoard(boardID)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	summary, err := boardService.DeleteBoard(boardID, version)
 	if err != nil {
 		if strings.Contains(err.Error(), "board not found or already deleted") {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to delete board: " + err.Error()})
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	c.JSON(http.StatusOK, summary)
 }
 
 // GetBoardDetails handles retrieving a full board with all its lists and cards.
 // @Summary Get full board details
-// @Description Retrieves a board and all of its nested lists, cards, labels, etc.
+// @Description Retrieves a board with its lists in rank order, and each list's cards in rank order with their labels, attachments and comment count. Lists holding more cards than their WIP limit have over_wip_limit set. Archived lists and cards are left out unless include_archived is true. Requires read access to the board.
 // @Tags Boards
 // @Security ApiKeyAuth
 // @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
 // @Param boardID path string true "Board ID"
+// @Param include_archived query bool false "Include archived lists and cards"
 // @Success 200 {object} models.Board "Full board details"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
-// @Router /boards/{boardID}/details [get]
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/details [get]
 func GetBoardDetails(c *gin.Context) {
 	boardID := c.Param("boardID")
 
-	board, err := boardService.GetBoardDetails(boardID)
+	var query models.ArchiveQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	board, err := boardService.GetBoardDetails(boardID, query.IncludeArchived)
 	if err != nil {
 		if strings.Contains(err.Error(), "board not found") {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
@@ -8,6 +8,7 @@ import (
 	"kanban-app/api/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 var cardService *services.CardService
@@ -18,7 +19,7 @@ func init() {
 
 // CreateCard handles creating a new card within a list.
 // @Summary Create a new card
-// @Description Creates a new card within a specified list. User must own the list.
+// @Description Creates a new card within a specified list. Requires write access to the list. If the list already holds as many cards as its WIP limit, the board's WIP policy decides: strict refuses the card with 409 Conflict, and warn lets it in with wip_limit_exceeded set on the returned card.
 // @Tags Cards
 // @Security ApiKeyAuth
 // @Accept json
@@ -29,10 +30,12 @@ func init() {
 // @Param listID path string true "List ID"
 // @Param card body models.CreateCardRequest true "Card creation details"
 // @Success 201 {object} models.Card "Card created successfully"
+// @Header 201 {string} ETag "Version of the card"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict (the list is archived or at its WIP limit)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/lists/{listID}/cards [post]
 func CreateCard(c *gin.Context) {
@@ -53,24 +56,27 @@ func CreateCard(c *gin.Context) {
 		userID.(string),
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to create card: " + err.Error()})
+		handleCardMoveError(c, "Failed to create card: ", err)
 		return
 	}
 
+	setETag(c, card.Version)
 	c.JSON(http.StatusCreated, card)
 }
 
 // GetCards handles retrieving all cards for a specific list.
 // @Summary Get all cards in a list
-// @Description Retrieves all cards within a specified list. User must own the list.
+// @Description Retrieves the cards within a specified list in rank order. Archived cards are left out unless include_archived is true. Requires read access to the list.
 // @Tags Cards
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
 // @Param projectID path string true "Project ID"
 // @Param boardID path string true "Board ID"
 // @Param listID path string true "List ID"
+// @Param include_archived query bool false "Include archived cards"
 // @Produce json
 // @Success 200 {array} models.Card "List of cards"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
@@ -79,7 +85,13 @@ func CreateCard(c *gin.Context) {
 func GetCards(c *gin.Context) {
 	listID := c.Param("listID")
 
-	cards, err := cardService.GetCardsByListID(listID)
+	var query models.ArchiveQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	cards, err := cardService.GetCardsByListID(listID, query.IncludeArchived)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve cards: " + err.Error()})
 		return
@@ -90,7 +102,7 @@ func GetCards(c *gin.Context) {
 
 // GetCardByID handles retrieving a specific card within a list.
 // @Summary Get card by ID
-// @Description Retrieves a specific card by its ID within a specified list. User must own the list.
+// @Description Retrieves a specific card by its ID within a specified list. Requires read access to the list.
 // @Tags Cards
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
@@ -100,6 +112,7 @@ func GetCards(c *gin.Context) {
 // @Param cardID path string true "Card ID"
 // @Produce json
 // @Success 200 {object} models.Card "Card details"
+// @Header 200 {string} ETag "Version of the card"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
@@ -119,12 +132,13 @@ func GetCardByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, card.Version)
 	c.JSON(http.StatusOK, card)
 }
 
 // UpdateCard handles updating an existing card within a list.
 // @Summary Update a card
-// @Description Updates a specific card by its ID within a specified list. User must own the list. Supports moving card to another list.
+// @Description Updates a specific card by its ID within a specified list. Requires write access to the list. Only the fields present in the body are changed; send null to clear the description, notes or due date. Supports moving the card: a position puts it at that 1-based place in its list, or in the list given alongside it, and a list alone puts it at the end of that list. Moves are checked the same way as by the move endpoint. If the destination list already holds as many cards as its WIP limit, the board's WIP policy decides: strict refuses the card with 409 Conflict, and warn lets it in with wip_limit_exceeded set on the returned card.
 // @Tags Cards
 // @Security ApiKeyAuth
 // @Accept json
@@ -135,35 +149,315 @@ func GetCardByID(c *gin.Context) {
 // @Param listID path string true "List ID"
 // @Param cardID path string true "Card ID"
 // @Param card body models.UpdateCardRequest true "Card update details"
+// @Param If-Match header string true "ETag of the version being changed, or *"
 // @Success 200 {object} models.Card "Card updated successfully"
+// @Header 200 {string} ETag "Version of the card"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict (the destination list is archived or at its WIP limit)"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the card has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/lists/{listID}/cards/{cardID} [put]
 func UpdateCard(c *gin.Context) {
 	cardID := c.Param("cardID")
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req models.UpdateCardRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	if req.ListID != "" && req.Position != nil {
-		if err := cardService.MoveCard(cardID, req.ListID, *req.Position); err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to move card: " + err.Error()})
-			return
+	updateCard(c, cardID, version, req)
+}
+
+// PatchCard handles applying a JSON Merge Patch (RFC 7386) to a card.
+// @Summary Patch a card
+// @Description Applies a JSON Merge Patch to a specific card. Requires write access to the list. Members set in the patch replace the card's values, null members clear the description, notes or due date, and absent members are left unchanged. Moves are checked the same way as by a full update.
+// @Tags Cards
+// @Security ApiKeyAuth
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Param boardID path string true "Board ID"
+// @Param listID path string true "List ID"
+// @Param cardID path string true "Card ID"
+// @Param patch body models.UpdateCardRequest true "Merge patch document"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.Card "Card updated successfully"
+// @Header 200 {string} ETag "Version of the card"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict (the destination list is archived or at its WIP limit)"
+// @Failure 415 {object} models.ErrorResponse "Unsupported Media Type"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the card has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/lists/{listID}/cards/{cardID} [patch]
+func PatchCard(c *gin.Context) {
+	cardID := c.Param("cardID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if contentType := c.ContentType(); contentType != "application/merge-patch+json" && contentType != "application/json" {
+		c.JSON(http.StatusUnsupportedMediaType, models.ErrorResponse{Message: "Content-Type must be application/merge-patch+json"})
+		return
+	}
+
+	// A card has no nested objects, so merging the patch is the same as
+	// applying each member present in the document.
+	var req models.UpdateCardRequest
+	if err := c.ShouldBindWith(&req, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	updateCard(c, cardID, version, req)
+}
+
+func updateCard(c *gin.Context, cardID string, version int, req models.UpdateCardRequest) {
+	userID, _ := c.Get("userID")
+
+	card, err := cardService.UpdateCard(cardID, version, userID.(string), req)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "cannot be null"):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		default:
+			handleCardMoveError(c, "Failed to update card: ", err)
 		}
+		return
+	}
+
+	setETag(c, card.Version)
+	c.JSON(http.StatusOK, card)
+}
+
+// MoveCard handles moving a card within its list or to another list.
+// @Summary Move a card
+// @Description Moves a card to a 1-based position in a list, or to the end of the list when no position is given or it is past the last card. The list may be on another board of the same organization, which requires write access to that list too; labels of the old board are removed from the card. Only the moved card's rank changes. Returns the card with the new ordering of the list it entered and, when it changed lists, of the list it left. Requires write access to the card. If the destination list already holds as many cards as its WIP limit, the board's WIP policy decides: strict refuses the card with 409 Conflict, and warn lets it in with wip_limit_exceeded set on the returned card.
+// @Tags Cards
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Param move body models.MoveCardRequest true "Destination list and position"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.CardMoveResult "Card moved successfully"
+// @Header 200 {string} ETag "Version of the card"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict (the destination list is archived or at its WIP limit)"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the card has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/move [post]
+func MoveCard(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req models.MoveCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
 	}
 
-	c.Status(http.StatusOK)
+	result, err := cardService.MoveCardToList(c.Param("cardID"), version, userID.(string), req)
+	if err != nil {
+		handleCardMoveError(c, "Failed to move card: ", err)
+		return
+	}
+
+	setETag(c, result.Card.Version)
+	c.JSON(http.StatusOK, result)
+}
+
+// ArchiveCard handles archiving a card.
+// @Summary Archive a card
+// @Description Takes a card off its list without deleting it. Archived cards are left out of board views unless include_archived is set, and don't count towards positions in the list. Requires write access to the card.
+// @Tags Cards
+// @Security ApiKeyAuth
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.Card "Card archived successfully"
+// @Header 200 {string} ETag "Version of the card"
+// @Failure 400 {object} models.ErrorResponse "Bad Request (the card is already archived)"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the card has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/archive [post]
+func ArchiveCard(c *gin.Context) {
+	changeCardArchived(c, cardService.ArchiveCard, "Failed to archive card: ")
+}
+
+// UnarchiveCard handles putting an archived card back on its list.
+// @Summary Unarchive a card
+// @Description Puts an archived card back on its list where it was. The card counts towards the list's WIP limit again: on a board with the strict policy a full list refuses it, and with the warn policy it is unarchived with wip_limit_exceeded set. Requires write access to the card.
+// @Tags Cards
+// @Security ApiKeyAuth
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.Card "Card unarchived successfully"
+// @Header 200 {string} ETag "Version of the card"
+// @Failure 400 {object} models.ErrorResponse "Bad Request (the card is not archived)"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict (the list is archived or its WIP limit is reached)"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the card has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/unarchive [post]
+func UnarchiveCard(c *gin.Context) {
+	changeCardArchived(c, cardService.UnarchiveCard, "Failed to unarchive card: ")
+}
+
+func changeCardArchived(c *gin.Context, change func(cardID string, version int, actorID string) (*models.Card, error), prefix string) {
+	userID, _ := c.Get("userID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	card, err := change(c.Param("cardID"), version, userID.(string))
+	if err != nil {
+		handleCardMoveError(c, prefix, err)
+		return
+	}
+
+	setETag(c, card.Version)
+	c.JSON(http.StatusOK, card)
+}
+
+// ArchiveListCards handles archiving every card in a list.
+// @Summary Archive all cards in a list
+// @Description Archives every card in the list that isn't archived yet, in one transaction, and returns their IDs. The list itself stays on the board. Requires write access to the list.
+// @Tags Cards
+// @Security ApiKeyAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Param boardID path string true "Board ID"
+// @Param listID path string true "List ID"
+// @Success 200 {object} models.ArchivedCards "Cards archived successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/lists/{listID}/cards/archive [post]
+func ArchiveListCards(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	result, err := cardService.ArchiveListCards(c.Param("listID"), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to archive cards: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func handleCardMoveError(c *gin.Context, prefix string, err error) {
+	status := cardErrorStatus(err.Error())
+	if status == http.StatusInternalServerError {
+		c.JSON(status, models.ErrorResponse{Message: prefix + err.Error()})
+		return
+	}
+	c.JSON(status, models.ErrorResponse{Message: err.Error()})
+}
+
+// cardErrorStatus maps the error of a change to a card to its HTTP status.
+func cardErrorStatus(message string) int {
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "not authorized"):
+		return http.StatusForbidden
+	case strings.Contains(message, "version mismatch"):
+		return http.StatusPreconditionFailed
+	case strings.Contains(message, "WIP limit reached"), strings.Contains(message, "list is archived"):
+		return http.StatusConflict
+	case strings.Contains(message, "invalid"), strings.Contains(message, "another organization"),
+		strings.Contains(message, "not available on this board"), strings.Contains(message, "not a member"),
+		strings.Contains(message, "already archived"), strings.Contains(message, "not archived"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// BulkUpdateCards handles applying a batch of card operations on a board.
+// @Summary Apply bulk card operations
+// @Description Applies up to 100 operations to cards on the board in one transaction: move (list_id and/or position), set_labels (label_ids), set_due_date (due_date, or null to clear it), assign (user_ids, replacing the assignees), archive and delete. Each operation is authorized on its card as if sent alone, so delete needs delete access and the rest write access, and a move to another list also needs write access to that list. A version makes an operation apply only to that version of the card. A move into another list follows the WIP policy of its board, like a single move. If any operation fails, none are applied: the response has the status the failed operation would have had alone, with its error in the results, the operations before it rolled back and the rest skipped. Requires read access to the board.
+// @Tags Cards
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Param operations body models.BulkCardRequest true "Operations to apply, in order"
+// @Success 200 {object} models.BulkCardResponse "Every operation was applied"
+// @Failure 400 {object} models.BulkCardResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.BulkCardResponse "Forbidden"
+// @Failure 404 {object} models.BulkCardResponse "Not Found"
+// @Failure 409 {object} models.BulkCardResponse "Conflict (a destination list is archived or at its WIP limit)"
+// @Failure 412 {object} models.BulkCardResponse "Precondition Failed (a card has changed since it was read)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/cards/bulk [post]
+func BulkUpdateCards(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req models.BulkCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	response, err := cardService.BulkUpdate(c.Param("boardID"), userID.(string), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to apply bulk operations: " + err.Error()})
+		return
+	}
+
+	if !response.Applied {
+		for _, result := range response.Results {
+			if result.Status == models.BulkResultFailed {
+				c.JSON(cardErrorStatus(result.Error), response)
+				return
+			}
+		}
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // DeleteCard handles deleting a card within a list.
 // @Summary Delete a card
-// @Description Deletes a specific card by its ID within a specified list. User must own the list.
+// @Description Deletes a specific card by its ID within a specified list. Requires delete access to the list. The card is moved to the trash and can be restored until the retention period expires.
 // @Tags Cards
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
@@ -171,49 +465,76 @@ func UpdateCard(c *gin.Context) {
 // @Param boardID path string true "Board ID"
 // @Param listID path string true "List ID"
 // @Param cardID path string true "Card ID"
-// @Success 204 "No Content"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.DeletionSummary "Counts of everything moved to the trash"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the card has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/lists/{listID}/cards/{cardID} [delete]
 func DeleteCard(c *gin.Context) {
+	userID, _ := c.Get("userID")
 	cardID := c.Param("cardID")
 
-	err := cardService.DeleteCard(cardID)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	summary, err := cardService.DeleteCard(cardID, version, userID.(string))
 	if err != nil {
 		if strings.Contains(err.Error(), "card not found or already deleted") {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to delete card: " + err.Error()})
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	c.JSON(http.StatusOK, summary)
 }
 
 // AddLabelToCard handles associating a label with a card.
 // @Summary Add label to card
-// @Description Associates an existing label with a specific card.
+// @Description Associates an existing label with a specific card. The label must belong to the card's board or organization. Requires write access to the card.
 // @Tags Cards
 // @Security ApiKeyAuth
 // @Param cardID path string true "Card ID"
 // @Param labelID path string true "Label ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
 // @Success 200 {object} models.Card "Card with label associated"
+// @Header 200 {string} ETag "Version of the card"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the card has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /cards/{cardID}/labels/{labelID} [post]
 func AddLabelToCard(c *gin.Context) {
+	userID, _ := c.Get("userID")
 	cardID := c.Param("cardID")
 	labelID := c.Param("labelID")
 
-	card, err := cardService.AddLabelToCard(cardID, labelID)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	card, err := cardService.AddLabelToCard(cardID, version, labelID, userID.(string))
 	if err != nil {
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		if strings.Contains(err.Error(), "card not found") || strings.Contains(err.Error(), "label not found") {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
 			return
@@ -222,33 +543,52 @@ func AddLabelToCard(c *gin.Context) {
 			c.JSON(http.StatusConflict, models.ErrorResponse{Message: err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "not available on this board") {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to add label to card: " + err.Error()})
 		return
 	}
 
+	setETag(c, card.Version)
 	c.JSON(http.StatusOK, card)
 }
 
 // RemoveLabelFromCard handles disassociating a label from a card.
 // @Summary Remove label from card
-// @Description Disassociates a label from a specific card.
+// @Description Disassociates a label from a specific card. Requires write access to the card.
 // @Tags Cards
 // @Security ApiKeyAuth
 // @Param cardID path string true "Card ID"
 // @Param labelID path string true "Label ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
 // @Success 200 {object} models.Card "Card with label disassociated"
+// @Header 200 {string} ETag "Version of the card"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the card has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /cards/{cardID}/labels/{labelID} [delete]
 func RemoveLabelFromCard(c *gin.Context) {
+	userID, _ := c.Get("userID")
 	cardID := c.Param("cardID")
 	labelID := c.Param("labelID")
 
-	card, err := cardService.RemoveLabelFromCard(cardID, labelID)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	card, err := cardService.RemoveLabelFromCard(cardID, version, labelID, userID.(string))
 	if err != nil {
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		if strings.Contains(err.Error(), "card not found") || strings.Contains(err.Error(), "label not found") {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
 			return
@@ -261,5 +601,301 @@ func RemoveLabelFromCard(c *gin.Context) {
 		return
 	}
 
+	setETag(c, card.Version)
 	c.JSON(http.StatusOK, card)
-}
\ No newline at end of file
+}
+
+// SetCardLabels handles replacing all labels on a card.
+// @Summary Set card labels
+// @Description Replaces the labels on a specific card with the given set; an empty list removes them all. Every label must belong to the card's board or organization. Requires write access to the card.
+// @Tags Cards
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Param labels body models.SetCardLabelsRequest true "Label IDs"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.Card "Card with its new labels"
+// @Header 200 {string} ETag "Version of the card"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the card has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/labels [put]
+func SetCardLabels(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	cardID := c.Param("cardID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetCardLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	card, err := cardService.SetCardLabels(cardID, version, req.LabelIDs, userID.(string))
+	if err != nil {
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "card not found") || strings.Contains(err.Error(), "label not found") {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "not available on this board") {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to set card labels: " + err.Error()})
+		return
+	}
+
+	setETag(c, card.Version)
+	c.JSON(http.StatusOK, card)
+}
+
+// GetCardsByLabel handles retrieving the cards on a board that carry a label.
+// @Summary Get cards by label
+// @Description Retrieves every card on a board that carries the given label, ordered by list and then card rank. Archived cards and the cards of archived lists are left out unless include_archived is true. Requires read access to the board.
+// @Tags Cards
+// @Security ApiKeyAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Param boardID path string true "Board ID"
+// @Param labelID path string true "Label ID"
+// @Param include_archived query bool false "Include archived cards"
+// @Success 200 {array} models.Card "List of cards"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/labels/{labelID}/cards [get]
+func GetCardsByLabel(c *gin.Context) {
+	boardID := c.Param("boardID")
+	labelID := c.Param("labelID")
+
+	var query models.ArchiveQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	cards, err := cardService.GetCardsByLabel(c.Param("orgID"), boardID, labelID, query.IncludeArchived)
+	if err != nil {
+		if strings.Contains(err.Error(), "label not found") || strings.Contains(err.Error(), "not available on this board") {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve cards: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cards)
+}
+
+// AddCardAssignee handles assigning a user to a card.
+// @Summary Assign a user to a card
+// @Description Assigns a member of the card's organization to a specific card. Requires write access to the card.
+// @Tags Cards
+// @Security ApiKeyAuth
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Param userID path string true "User ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.Card "Card with the user assigned"
+// @Header 200 {string} ETag "Version of the card"
+// @Failure 400 {object} models.ErrorResponse "Bad Request (if the user is not a member of the organization)"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the card has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/assignees/{userID} [post]
+func AddCardAssignee(c *gin.Context) {
+	actorID, _ := c.Get("userID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	card, err := cardService.AddAssignee(c.Param("cardID"), version, c.Param("userID"), actorID.(string))
+	if err != nil {
+		handleCardUserError(c, "Failed to assign user: ", err)
+		return
+	}
+
+	setETag(c, card.Version)
+	c.JSON(http.StatusOK, card)
+}
+
+// RemoveCardAssignee handles unassigning a user from a card.
+// @Summary Unassign a user from a card
+// @Description Removes a user from the assignees of a specific card. Requires write access to the card.
+// @Tags Cards
+// @Security ApiKeyAuth
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Param userID path string true "User ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.Card "Card with the user unassigned"
+// @Header 200 {string} ETag "Version of the card"
+// @Failure 400 {object} models.ErrorResponse "Bad Request (if the user is not a member of the organization)"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the card has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/assignees/{userID} [delete]
+func RemoveCardAssignee(c *gin.Context) {
+	actorID, _ := c.Get("userID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	card, err := cardService.RemoveAssignee(c.Param("cardID"), version, c.Param("userID"), actorID.(string))
+	if err != nil {
+		handleCardUserError(c, "Failed to unassign user: ", err)
+		return
+	}
+
+	setETag(c, card.Version)
+	c.JSON(http.StatusOK, card)
+}
+
+// AddCardWatcher handles adding a watcher to a card.
+// @Summary Watch a card
+// @Description Adds a member of the card's organization to the watchers of a specific card. Requires write access to the card.
+// @Tags Cards
+// @Security ApiKeyAuth
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Param userID path string true "User ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.Card "Card with the watcher added"
+// @Header 200 {string} ETag "Version of the card"
+// @Failure 400 {object} models.ErrorResponse "Bad Request (if the user is not a member of the organization)"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the card has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/watchers/{userID} [post]
+func AddCardWatcher(c *gin.Context) {
+	actorID, _ := c.Get("userID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	card, err := cardService.AddWatcher(c.Param("cardID"), version, c.Param("userID"), actorID.(string))
+	if err != nil {
+		handleCardUserError(c, "Failed to add watcher: ", err)
+		return
+	}
+
+	setETag(c, card.Version)
+	c.JSON(http.StatusOK, card)
+}
+
+// RemoveCardWatcher handles removing a watcher from a card.
+// @Summary Unwatch a card
+// @Description Removes a user from the watchers of a specific card. Requires write access to the card.
+// @Tags Cards
+// @Security ApiKeyAuth
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Param userID path string true "User ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.Card "Card with the watcher removed"
+// @Header 200 {string} ETag "Version of the card"
+// @Failure 400 {object} models.ErrorResponse "Bad Request (if the user is not a member of the organization)"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the card has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/watchers/{userID} [delete]
+func RemoveCardWatcher(c *gin.Context) {
+	actorID, _ := c.Get("userID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	card, err := cardService.RemoveWatcher(c.Param("cardID"), version, c.Param("userID"), actorID.(string))
+	if err != nil {
+		handleCardUserError(c, "Failed to remove watcher: ", err)
+		return
+	}
+
+	setETag(c, card.Version)
+	c.JSON(http.StatusOK, card)
+}
+
+// GetMyCards handles retrieving the cards assigned to the authenticated user.
+// @Summary Get my cards
+// @Description Retrieves every card assigned to the authenticated user across all boards, ordered by due date. Archived cards and the cards of archived lists are left out unless include_archived is true.
+// @Tags Cards
+// @Security ApiKeyAuth
+// @Produce json
+// @Param include_archived query bool false "Include archived cards"
+// @Success 200 {array} models.Card "List of assigned cards"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /me/cards [get]
+func GetMyCards(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var query models.ArchiveQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	cards, err := cardService.GetAssignedCards(userID.(string), query.IncludeArchived)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve cards: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cards)
+}
+
+func handleCardUserError(c *gin.Context, prefix string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "card not found") || strings.Contains(err.Error(), "user not"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "not a member"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "user already"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "version mismatch"):
+		c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: prefix + err.Error()})
+	}
+}
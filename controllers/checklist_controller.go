@@ -0,0 +1,284 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"kanban-app/api/models"
+	"kanban-app/api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var checklistService *services.ChecklistService
+
+func init() {
+	checklistService = services.NewChecklistService()
+}
+
+// GetChecklists handles retrieving the checklists of a card.
+// @Summary Get card checklists
+// @Description Retrieves the checklists of a card in order, each with its items in order. Requires read access to the card.
+// @Tags Checklists
+// @Security ApiKeyAuth
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Success 200 {array} models.Checklist "List of checklists"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/checklists [get]
+func GetChecklists(c *gin.Context) {
+	cardID := c.Param("cardID")
+
+	checklists, err := checklistService.GetChecklists(cardID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve checklists: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, checklists)
+}
+
+// CreateChecklist handles adding a checklist to a card.
+// @Summary Create a checklist
+// @Description Adds a checklist at the end of a card's checklists. Requires write access to the card.
+// @Tags Checklists
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Param checklist body models.CreateChecklistRequest true "Checklist creation details"
+// @Success 201 {object} models.Checklist "Checklist created successfully"
+// @Header 201 {string} ETag "Version of the checklist"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/checklists [post]
+func CreateChecklist(c *gin.Context) {
+	cardID := c.Param("cardID")
+
+	var req models.CreateChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	checklist, err := checklistService.CreateChecklist(cardID, req.Title)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to create checklist: " + err.Error()})
+		return
+	}
+
+	setETag(c, checklist.Version)
+	c.JSON(http.StatusCreated, checklist)
+}
+
+// UpdateChecklist handles renaming or reordering a checklist.
+// @Summary Update a checklist
+// @Description Renames a checklist and/or moves it to a new position among the card's checklists. Positions past the end are clamped. Requires write access to the card.
+// @Tags Checklists
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Param checklistID path string true "Checklist ID"
+// @Param checklist body models.UpdateChecklistRequest true "Checklist update details"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.Checklist "Checklist updated successfully"
+// @Header 200 {string} ETag "Version of the checklist"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the checklist has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/checklists/{checklistID} [put]
+func UpdateChecklist(c *gin.Context) {
+	checklistID := c.Param("checklistID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	checklist, err := checklistService.UpdateChecklist(checklistID, version, req)
+	if err != nil {
+		handleChecklistError(c, "Failed to update checklist: ", err)
+		return
+	}
+
+	setETag(c, checklist.Version)
+	c.JSON(http.StatusOK, checklist)
+}
+
+// DeleteChecklist handles deleting a checklist and its items.
+// @Summary Delete a checklist
+// @Description Deletes a checklist together with its items. Requires write access to the card.
+// @Tags Checklists
+// @Security ApiKeyAuth
+// @Param cardID path string true "Card ID"
+// @Param checklistID path string true "Checklist ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the checklist has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/checklists/{checklistID} [delete]
+func DeleteChecklist(c *gin.Context) {
+	checklistID := c.Param("checklistID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := checklistService.DeleteChecklist(checklistID, version); err != nil {
+		handleChecklistError(c, "Failed to delete checklist: ", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CreateChecklistItem handles adding an item to a checklist.
+// @Summary Create a checklist item
+// @Description Adds an item at the end of a checklist, optionally assigned to a member of the card's organization and with a due date. Requires write access to the card.
+// @Tags Checklists
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Param checklistID path string true "Checklist ID"
+// @Param item body models.CreateChecklistItemRequest true "Item creation details"
+// @Success 201 {object} models.ChecklistItem "Item created successfully"
+// @Header 201 {string} ETag "Version of the checklist item"
+// @Failure 400 {object} models.ErrorResponse "Bad Request (if the assignee is not a member of the organization)"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/checklists/{checklistID}/items [post]
+func CreateChecklistItem(c *gin.Context) {
+	checklistID := c.Param("checklistID")
+
+	var req models.CreateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	item, err := checklistService.CreateItem(checklistID, req)
+	if err != nil {
+		handleChecklistError(c, "Failed to create checklist item: ", err)
+		return
+	}
+
+	setETag(c, item.Version)
+	c.JSON(http.StatusCreated, item)
+}
+
+// UpdateChecklistItem handles editing, completing or reordering a checklist item.
+// @Summary Update a checklist item
+// @Description Changes only the fields present in the body: content, completion, assignee, due date or position within the checklist. Send null to clear the assignee or due date. Requires write access to the card.
+// @Tags Checklists
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Param checklistID path string true "Checklist ID"
+// @Param itemID path string true "Item ID"
+// @Param item body models.UpdateChecklistItemRequest true "Item update details"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.ChecklistItem "Item updated successfully"
+// @Header 200 {string} ETag "Version of the checklist item"
+// @Failure 400 {object} models.ErrorResponse "Bad Request (if the assignee is not a member of the organization)"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the checklist item has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/checklists/{checklistID}/items/{itemID} [put]
+func UpdateChecklistItem(c *gin.Context) {
+	itemID := c.Param("itemID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	item, err := checklistService.UpdateItem(itemID, version, req)
+	if err != nil {
+		handleChecklistError(c, "Failed to update checklist item: ", err)
+		return
+	}
+
+	setETag(c, item.Version)
+	c.JSON(http.StatusOK, item)
+}
+
+// DeleteChecklistItem handles deleting a checklist item.
+// @Summary Delete a checklist item
+// @Description Deletes an item from a checklist. Requires write access to the card.
+// @Tags Checklists
+// @Security ApiKeyAuth
+// @Param cardID path string true "Card ID"
+// @Param checklistID path string true "Checklist ID"
+// @Param itemID path string true "Item ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the checklist item has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /cards/{cardID}/checklists/{checklistID}/items/{itemID} [delete]
+func DeleteChecklistItem(c *gin.Context) {
+	itemID := c.Param("itemID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := checklistService.DeleteItem(itemID, version); err != nil {
+		handleChecklistError(c, "Failed to delete checklist item: ", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func handleChecklistError(c *gin.Context, prefix string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "not a member"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "version mismatch"):
+		c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: prefix + err.Error()})
+	}
+}
@@ -3,6 +3,7 @@ package controllers
 
 import (
 	"net/http"
+	"strings"
 
 	"kanban-app/api/models"
 	"kanban-app/api/services"
@@ -26,6 +27,7 @@ func init() {
 // @Param cardID path string true "Card ID"
 // @Param comment body models.CreateCommentRequest true "Comment creation details"
 // @Success 201 {object} models.Comment "Comment created successfully"
+// @Header 201 {string} ETag "Version of the comment"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
@@ -48,6 +50,7 @@ func CreateComment(c *gin.Context) {
 		return
 	}
 
+	setETag(c, comment.Version)
 	c.JSON(http.StatusCreated, comment)
 }
 
@@ -58,16 +61,29 @@ func CreateComment(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Param cardID path string true "Card ID"
 // @Param commentID path string true "Comment ID"
+// @Param If-Match header string true "ETag of the version being deleted, or *"
 // @Success 204 "No Content"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the comment has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /cards/{cardID}/comments/{commentID} [delete]
 func DeleteComment(c *gin.Context) {
+	userID, _ := c.Get("userID")
 	commentID := c.Param("commentID")
 
-	if err := commentService.DeleteComment(commentID); err != nil {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := commentService.DeleteComment(commentID, version, userID.(string)); err != nil {
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to delete comment: " + err.Error()})
 		return
 	}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"kanban-app/api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// keepAliveInterval keeps idle streams from being closed by proxies.
+const keepAliveInterval = 25 * time.Second
+
+var eventService *services.EventService
+
+func init() {
+	eventService = services.NewEventService()
+}
+
+// StreamBoardEvents handles streaming a board's changes as Server-Sent Events.
+// @Summary Stream board events
+// @Description Streams changes to the board's lists, cards, comments, labels and attachments as Server-Sent Events. Each event is named after its type, e.g. card.moved, and its data is a models.BoardEvent. The stream ends if the client falls too far behind, after which it should reload the board and reconnect. Requires read access to the board.
+// @Tags Boards
+// @Security ApiKeyAuth
+// @Produce text/event-stream
+// @Param boardID path string true "Board ID"
+// @Success 200 {object} models.BoardEvent "Stream of board events"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Router /boards/{boardID}/events [get]
+func StreamBoardEvents(c *gin.Context) {
+	events, unsubscribe := eventService.Subscribe(c.Param("boardID"))
+	defer unsubscribe()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
@@ -16,52 +16,90 @@ func init() {
 	labelService = services.NewLabelService()
 }
 
-// CreateLabel handles creating a new label.
-// @Summary Create a new label
-// @Description Creates a new reusable label.
+// CreateOrganizationLabel handles creating a label shared by every board of an organization.
+// @Summary Create an organization label
+// @Description Creates a label usable on every board of the organization. Requires write access to the organization.
 // @Tags Labels
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
+// @Param orgID path string true "Organization ID"
 // @Param label body models.CreateLabelRequest true "Label creation details"
 // @Success 201 {object} models.Label "Label created successfully"
+// @Header 201 {string} ETag "Version of the label"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 409 {object} models.ErrorResponse "Conflict"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
-// @Router /labels [post]
-func CreateLabel(c *gin.Context) {
-	var req models.CreateLabelRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
-		return
-	}
+// @Router /organizations/{orgID}/labels [post]
+func CreateOrganizationLabel(c *gin.Context) {
+	createLabel(c, c.Param("orgID"), "")
+}
 
-	label, err := labelService.CreateLabel(req.Name, req.Color)
+// GetOrganizationLabels handles retrieving the labels shared by an organization.
+// @Summary Get organization labels
+// @Description Retrieves the labels shared by every board of the organization. Requires read access to the organization.
+// @Tags Labels
+// @Security ApiKeyAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Success 200 {array} models.Label "List of labels"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/labels [get]
+func GetOrganizationLabels(c *gin.Context) {
+	labels, err := labelService.GetOrganizationLabels(c.Param("orgID"))
 	if err != nil {
-		if strings.Contains(err.Error(), "label name already exists") {
-			c.JSON(http.StatusConflict, models.ErrorResponse{Message: err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to create label: " + err.Error()})
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve labels: " + err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, label)
+	c.JSON(http.StatusOK, labels)
 }
 
-// GetAllLabels handles retrieving all labels.
-// @Summary Get all labels
-// @Description Retrieves all reusable labels.
+// CreateBoardLabel handles creating a label for a single board.
+// @Summary Create a board label
+// @Description Creates a label usable only on this board. Requires write access to the board.
 // @Tags Labels
 // @Security ApiKeyAuth
+// @Accept json
 // @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Param boardID path string true "Board ID"
+// @Param label body models.CreateLabelRequest true "Label creation details"
+// @Success 201 {object} models.Label "Label created successfully"
+// @Header 201 {string} ETag "Version of the label"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/labels [post]
+func CreateBoardLabel(c *gin.Context) {
+	createLabel(c, c.Param("orgID"), c.Param("boardID"))
+}
+
+// GetBoardLabels handles retrieving the labels usable on a board.
+// @Summary Get board labels
+// @Description Retrieves the labels usable on a board, both its own and those shared by its organization. Requires read access to the board.
+// @Tags Labels
+// @Security ApiKeyAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Param boardID path string true "Board ID"
 // @Success 200 {array} models.Label "List of labels"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
-// @Router /labels [get]
-func GetAllLabels(c *gin.Context) {
-	labels, err := labelService.GetAllLabels()
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/labels [get]
+func GetBoardLabels(c *gin.Context) {
+	labels, err := labelService.GetBoardLabels(c.Param("orgID"), c.Param("boardID"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve labels: " + err.Error()})
 		return
@@ -70,15 +108,40 @@ func GetAllLabels(c *gin.Context) {
 	c.JSON(http.StatusOK, labels)
 }
 
+func createLabel(c *gin.Context, orgID, boardID string) {
+	userID, _ := c.Get("userID")
+
+	var req models.CreateLabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	label, err := labelService.CreateLabel(orgID, boardID, req.Name, req.Color, userID.(string))
+	if err != nil {
+		if strings.Contains(err.Error(), "label name already exists") {
+			c.JSON(http.StatusConflict, models.ErrorResponse{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to create label: " + err.Error()})
+		return
+	}
+
+	setETag(c, label.Version)
+	c.JSON(http.StatusCreated, label)
+}
+
 // GetLabelByID handles retrieving a label by ID.
 // @Summary Get label by ID
-// @Description Retrieves a specific label by its ID.
+// @Description Retrieves a specific label by its ID. Requires read access to the label's board or organization.
 // @Tags Labels
 // @Security ApiKeyAuth
 // @Param labelID path string true "Label ID"
 // @Produce json
 // @Success 200 {object} models.Label "Label details"
+// @Header 200 {string} ETag "Version of the label"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /labels/{labelID} [get]
@@ -95,68 +158,98 @@ func GetLabelByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, label.Version)
 	c.JSON(http.StatusOK, label)
 }
 
 // UpdateLabel handles updating an existing label.
 // @Summary Update a label
-// @Description Updates a specific label by its ID.
+// @Description Updates a specific label by its ID. Requires write access to the label's board or organization.
 // @Tags Labels
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param labelID path string true "Label ID"
 // @Param label body models.UpdateLabelRequest true "Label update details"
+// @Param If-Match header string true "ETag of the version being changed, or *"
 // @Success 200 {object} models.Label "Label updated successfully"
+// @Header 200 {string} ETag "Version of the label"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
 // @Failure 409 {object} models.ErrorResponse "Conflict"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the label has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /labels/{labelID} [put]
 func UpdateLabel(c *gin.Context) {
+	userID, _ := c.Get("userID")
 	labelID := c.Param("labelID")
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req models.UpdateLabelRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	updatedLabel, err := labelService.UpdateLabel(labelID, req)
+	updatedLabel, err := labelService.UpdateLabel(labelID, version, userID.(string), req)
 	if err != nil {
 		if strings.Contains(err.Error(), "label name already exists") {
 			c.JSON(http.StatusConflict, models.ErrorResponse{Message: err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to update label: " + err.Error()})
 		return
 	}
 
+	setETag(c, updatedLabel.Version)
 	c.JSON(http.StatusOK, updatedLabel)
 }
 
 // DeleteLabel handles deleting a label.
 // @Summary Delete a label
-// @Description Deletes a specific label by its ID.
+// @Description Deletes a specific label by its ID and removes it from every card. Requires delete access to the label's board or organization.
 // @Tags Labels
 // @Security ApiKeyAuth
 // @Param labelID path string true "Label ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
 // @Success 204 "No Content"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the label has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /labels/{labelID} [delete]
 func DeleteLabel(c *gin.Context) {
+	userID, _ := c.Get("userID")
 	labelID := c.Param("labelID")
 
-	err := labelService.DeleteLabel(labelID)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	err := labelService.DeleteLabel(labelID, version, userID.(string))
 	if err != nil {
 		if strings.Contains(err.Error(), "label not found or already deleted") {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to delete label: " + err.Error()})
 		return
 	}
@@ -18,7 +18,7 @@ func init() {
 
 // CreateList handles creating a new list within a board.
 // @Summary Create a new list
-// @Description Creates a new list within a specified board. User must own the board.
+// @Description Creates a new list within a specified board, optionally with a WIP limit on how many unarchived cards it holds and the flow state (not_started, started or done) board metrics count it in. Requires write access to the board.
 // @Tags Lists
 // @Security ApiKeyAuth
 // @Accept json
@@ -28,6 +28,7 @@ func init() {
 // @Param boardID path string true "Board ID"
 // @Param list body models.CreateListRequest true "List creation details"
 // @Success 201 {object} models.List "List created successfully"
+// @Header 201 {string} ETag "Version of the list"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
@@ -45,7 +46,7 @@ func CreateList(c *gin.Context) {
 		return
 	}
 
-	list, err := listService.CreateList(boardID, req.Name, userID.(string))
+	list, err := listService.CreateList(boardID, req.Name, req.WIPLimit, req.FlowState, userID.(string))
 	if err != nil {
 		if strings.Contains(err.Error(), "list name already exists") {
 			c.JSON(http.StatusConflict, models.ErrorResponse{Message: err.Error()})
@@ -55,19 +56,22 @@ func CreateList(c *gin.Context) {
 		return
 	}
 
+	setETag(c, list.Version)
 	c.JSON(http.StatusCreated, list)
 }
 
 // GetLists handles retrieving all lists for a specific board.
 // @Summary Get all lists in a board
-// @Description Retrieves all lists within a specified board. User must own the board.
+// @Description Retrieves the lists within a specified board in rank order. Archived lists are left out unless include_archived is true. Requires read access to the board.
 // @Tags Lists
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
 // @Param projectID path string true "Project ID"
 // @Param boardID path string true "Board ID"
+// @Param include_archived query bool false "Include archived lists"
 // @Produce json
 // @Success 200 {array} models.List "List of lists"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
@@ -76,7 +80,13 @@ func CreateList(c *gin.Context) {
 func GetLists(c *gin.Context) {
 	boardID := c.Param("boardID")
 
-	lists, err := listService.GetListsByBoardID(boardID)
+	var query models.ArchiveQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	lists, err := listService.GetListsByBoardID(boardID, query.IncludeArchived)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve lists: " + err.Error()})
 		return
@@ -87,7 +97,7 @@ func GetLists(c *gin.Context) {
 
 // GetListByID handles retrieving a specific list within a board.
 // @Summary Get list by ID
-// @Description Retrieves a specific list by its ID within a specified board. User must own the board.
+// @Description Retrieves a specific list by its ID within a specified board. Requires read access to the board.
 // @Tags Lists
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
@@ -96,6 +106,7 @@ func GetLists(c *gin.Context) {
 // @Param listID path string true "List ID"
 // @Produce json
 // @Success 200 {object} models.List "List details"
+// @Header 200 {string} ETag "Version of the list"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
@@ -114,12 +125,13 @@ func GetListByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, list.Version)
 	c.JSON(http.StatusOK, list)
 }
 
 // UpdateList handles updating an existing list within a board.
 // @Summary Update a list
-// @Description Updates a specific list by its ID within a specified board. User must own the board.
+// @Description Updates a specific list by its ID within a specified board. A position moves the list to that 1-based place on the board, or to the end if it is past the last list; only the moved list's rank changes. A wip_limit replaces the list's WIP limit, or removes it when null; cards already in the list stay when the limit is lowered below them. A flow_state sets the state board metrics count the list in, or removes it when null. Requires write access to the board.
 // @Tags Lists
 // @Security ApiKeyAuth
 // @Accept json
@@ -129,60 +141,170 @@ func GetListByID(c *gin.Context) {
 // @Param boardID path string true "Board ID"
 // @Param listID path string true "List ID"
 // @Param list body models.UpdateListRequest true "List update details"
+// @Param If-Match header string true "ETag of the version being changed, or *"
 // @Success 200 {object} models.List "List updated successfully"
+// @Header 200 {string} ETag "Version of the list"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
 // @Failure 409 {object} models.ErrorResponse "Conflict"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the list has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/lists/{listID} [put]
 func UpdateList(c *gin.Context) {
+	userID, _ := c.Get("userID")
 	listID := c.Param("listID")
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req models.UpdateListRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	if req.Position != nil {
-		if err := listService.MoveList(listID, *req.Position); err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to move list: " + err.Error()})
-			return
+	list, err := listService.UpdateList(listID, version, userID.(string), req)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "list not found"):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+		case strings.Contains(err.Error(), "list name already exists"):
+			c.JSON(http.StatusConflict, models.ErrorResponse{Message: err.Error()})
+		case strings.Contains(err.Error(), "invalid position"):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		case strings.Contains(err.Error(), "version mismatch"):
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to update list: " + err.Error()})
 		}
+		return
 	}
 
-	c.Status(http.StatusOK)
+	setETag(c, list.Version)
+	c.JSON(http.StatusOK, list)
 }
 
 // DeleteList handles deleting a list within a board.
 // @Summary Delete a list
-// @Description Deletes a specific list by its ID within a specified board. User must own the board.
+// @Description Deletes a specific list by its ID within a specified board. Requires delete access to the board. The list is moved to the trash and can be restored until the retention period expires.
 // @Tags Lists
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
 // @Param projectID path string true "Project ID"
 // @Param boardID path string true "Board ID"
 // @Param listID path string true "List ID"
-// @Success 204 "No Content"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.DeletionSummary "Counts of everything moved to the trash"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the list has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/lists/{listID} [delete]
 func DeleteList(c *gin.Context) {
+	userID, _ := c.Get("userID")
 	listID := c.Param("listID")
 
-	err := listService.DeleteList(listID)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	summary, err := listService.DeleteList(listID, version, userID.(string))
 	if err != nil {
 		if strings.Contains(err.Error(), "list not found or already deleted") {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to delete list: " + err.Error()})
 		return
 	}
 
-	c.Status(http.StatusNoContent)
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, summary)
+}
+// ArchiveList handles archiving a list within a board.
+// @Summary Archive a list
+// @Description Takes a list and its cards off the board without deleting them. Archived lists are left out of board views unless include_archived is set, and don't count towards positions on the board. Requires write access to the list.
+// @Tags Lists
+// @Security ApiKeyAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Param boardID path string true "Board ID"
+// @Param listID path string true "List ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.List "List archived successfully"
+// @Header 200 {string} ETag "Version of the list"
+// @Failure 400 {object} models.ErrorResponse "Bad Request (the list is already archived)"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the list has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/lists/{listID}/archive [post]
+func ArchiveList(c *gin.Context) {
+	changeListArchived(c, listService.ArchiveList, "Failed to archive list: ")
+}
+
+// UnarchiveList handles putting an archived list back on its board.
+// @Summary Unarchive a list
+// @Description Puts an archived list back on its board where it was, with the cards it had that aren't archived themselves. Requires write access to the list.
+// @Tags Lists
+// @Security ApiKeyAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Param boardID path string true "Board ID"
+// @Param listID path string true "List ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.List "List unarchived successfully"
+// @Header 200 {string} ETag "Version of the list"
+// @Failure 400 {object} models.ErrorResponse "Bad Request (the list is not archived)"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the list has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/lists/{listID}/unarchive [post]
+func UnarchiveList(c *gin.Context) {
+	changeListArchived(c, listService.UnarchiveList, "Failed to unarchive list: ")
+}
+
+func changeListArchived(c *gin.Context, change func(listID string, version int, actorID string) (*models.List, error), prefix string) {
+	userID, _ := c.Get("userID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	list, err := change(c.Param("listID"), version, userID.(string))
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "list not found"):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+		case strings.Contains(err.Error(), "already archived"), strings.Contains(err.Error(), "not archived"):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		case strings.Contains(err.Error(), "version mismatch"):
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: prefix + err.Error()})
+		}
+		return
+	}
+
+	setETag(c, list.Version)
+	c.JSON(http.StatusOK, list)
+}
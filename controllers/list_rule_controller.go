@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"kanban-app/api/models"
+	"kanban-app/api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var listRuleService *services.ListRuleService
+
+func init() {
+	listRuleService = services.NewListRuleService()
+}
+
+// CreateListRule handles adding an automation rule to a list.
+// @Summary Create a list rule
+// @Description Adds a rule that a background scheduler evaluates on the list every 15 minutes. An archive_after rule archives cards that have been in the list for more than the given number of days; cards that entered the list before entry times were tracked count from their last update. The rule is enabled unless enabled is false, and archives on behalf of the user who created it. Requires write access to the list.
+// @Tags List Rules
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Param listID path string true "List ID"
+// @Param rule body models.CreateListRuleRequest true "Rule details"
+// @Success 201 {object} models.ListRule "Rule created successfully"
+// @Header 201 {string} ETag "Version of the rule"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/lists/{listID}/rules [post]
+func CreateListRule(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req models.CreateListRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	rule, err := listRuleService.CreateRule(c.Param("listID"), userID.(string), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to create rule: " + err.Error()})
+		return
+	}
+
+	setETag(c, rule.Version)
+	c.JSON(http.StatusCreated, rule)
+}
+
+// GetListRules handles listing the automation rules of a list.
+// @Summary Get list rules
+// @Description Retrieves the rules of the list with when each last ran. Requires read access to the list.
+// @Tags List Rules
+// @Security ApiKeyAuth
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Param listID path string true "List ID"
+// @Success 200 {array} models.ListRule "List of rules"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/lists/{listID}/rules [get]
+func GetListRules(c *gin.Context) {
+	rules, err := listRuleService.GetRules(c.Param("listID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve rules: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// GetListRuleByID handles retrieving an automation rule of a list.
+// @Summary Get list rule by ID
+// @Description Retrieves a rule of the list. Requires read access to the list.
+// @Tags List Rules
+// @Security ApiKeyAuth
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Param listID path string true "List ID"
+// @Param ruleID path string true "Rule ID"
+// @Success 200 {object} models.ListRule "Rule details"
+// @Header 200 {string} ETag "Version of the rule"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/lists/{listID}/rules/{ruleID} [get]
+func GetListRuleByID(c *gin.Context) {
+	rule, err := listRuleService.GetRuleByID(c.Param("listID"), c.Param("ruleID"))
+	if err != nil {
+		handleListRuleError(c, "Failed to retrieve rule: ", err)
+		return
+	}
+
+	setETag(c, rule.Version)
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpdateListRule handles changing an automation rule of a list.
+// @Summary Update a list rule
+// @Description Changes only the fields present in the body: the number of days, or whether the rule is enabled. Requires write access to the list.
+// @Tags List Rules
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Param listID path string true "List ID"
+// @Param ruleID path string true "Rule ID"
+// @Param rule body models.UpdateListRuleRequest true "Rule update details"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.ListRule "Rule updated successfully"
+// @Header 200 {string} ETag "Version of the rule"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the rule has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/lists/{listID}/rules/{ruleID} [put]
+func UpdateListRule(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateListRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	rule, err := listRuleService.UpdateRule(c.Param("listID"), c.Param("ruleID"), version, req)
+	if err != nil {
+		handleListRuleError(c, "Failed to update rule: ", err)
+		return
+	}
+
+	setETag(c, rule.Version)
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteListRule handles deleting an automation rule of a list.
+// @Summary Delete a list rule
+// @Description Deletes a rule together with its run log. Requires write access to the list.
+// @Tags List Rules
+// @Security ApiKeyAuth
+// @Param boardID path string true "Board ID"
+// @Param listID path string true "List ID"
+// @Param ruleID path string true "Rule ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the rule has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/lists/{listID}/rules/{ruleID} [delete]
+func DeleteListRule(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := listRuleService.DeleteRule(c.Param("listID"), c.Param("ruleID"), version); err != nil {
+		handleListRuleError(c, "Failed to delete rule: ", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetListRuleRuns handles retrieving the run log of an automation rule.
+// @Summary Get list rule runs
+// @Description Retrieves a page of the rule's runs from the last 30 days, newest first, with the cards each run archived or the error that stopped it. Requires read access to the list.
+// @Tags List Rules
+// @Security ApiKeyAuth
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Param listID path string true "List ID"
+// @Param ruleID path string true "Rule ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Runs per page (default 50, max 200)"
+// @Success 200 {object} models.ListRuleRunPage "Page of runs"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/lists/{listID}/rules/{ruleID}/runs [get]
+func GetListRuleRuns(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	page, err := listRuleService.GetRuns(c.Param("listID"), c.Param("ruleID"), query)
+	if err != nil {
+		handleListRuleError(c, "Failed to retrieve rule runs: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func handleListRuleError(c *gin.Context, prefix string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "version mismatch"):
+		c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: prefix + err.Error()})
+	}
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"net/http"
+
+	"kanban-app/api/models"
+	"kanban-app/api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var metricsService *services.MetricsService
+
+func init() {
+	metricsService = services.NewMetricsService()
+}
+
+// GetBoardMetrics handles reporting on the flow of work across a board.
+// @Summary Get board metrics
+// @Description Reports on the board's lists that aren't archived, over a window of days ending today (UTC). For each list, in rank order: its flow state, how many unarchived cards it holds, its WIP limit, and whether it is over the limit. Flow states map lists to work not started, started or done; when no list has one, the first list counts as not started, the last as done and those in between as started. Cumulative flow gives the cards in each list at the end of every day, with archived cards dropping out except from done lists. Lead time (from creation) and cycle time (from first entering a started or done list) of the cards that reached the done lists in the window are given in days at the 50th, 85th and 95th percentiles, with throughput as the cards done per week starting on Monday. Aging WIP lists the unarchived cards in started lists, oldest first. Requires read access to the board.
+// @Tags Boards
+// @Security ApiKeyAuth
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Param days query int false "Days covered, ending today (default 30, max 365)"
+// @Success 200 {object} models.BoardMetrics "Board metrics"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /boards/{boardID}/metrics [get]
+func GetBoardMetrics(c *gin.Context) {
+	var query models.MetricsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	metrics, err := metricsService.GetBoardMetrics(c.Param("boardID"), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve board metrics: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
@@ -24,6 +24,7 @@ func init() {
 // @Produce json
 // @Param organization body models.CreateOrganizationRequest true "Organization creation details"
 // @Success 201 {object} models.Organization "Organization created successfully"
+// @Header 201 {string} ETag "Version of the organization"
 // @Failure 400 {object} models.ErrorResponse "Bad Request (e.g., validation error)"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized (if token is missing or invalid)"
 // @Failure 409 {object} models.ErrorResponse "Conflict (if organization name already exists)"
@@ -48,12 +49,13 @@ func CreateOrganization(c *gin.Context) {
 		return
 	}
 
+	setETag(c, org.Version)
 	c.JSON(http.StatusCreated, org)
 }
 
 // GetOrganizations handles retrieving all organizations for the authenticated user.
 // @Summary Get user's organizations
-// @Description Retrieves all organizations owned by the authenticated user.
+// @Description Retrieves all organizations the authenticated user is an accepted member of.
 // @Tags Organizations
 // @Security ApiKeyAuth
 // @Produce json
@@ -75,12 +77,13 @@ func GetOrganizations(c *gin.Context) {
 
 // GetOrganizationByID handles retrieving a single organization by its ID.
 // @Summary Get organization by ID
-// @Description Retrieves a specific organization by its ID, ensuring the authenticated user is the owner.
+// @Description Retrieves a specific organization by its ID, ensuring the authenticated user has access to it.
 // @Tags Organizations
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
 // @Produce json
 // @Success 200 {object} models.Organization "Organization details"
+// @Header 200 {string} ETag "Version of the organization"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden (if user is not the owner)"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
@@ -99,72 +102,100 @@ func GetOrganizationByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, org.Version)
 	c.JSON(http.StatusOK, org)
 }
 
 // UpdateOrganization handles updating an existing organization.
 // @Summary Update an organization
-// @Description Updates a specific organization by its ID, ensuring the authenticated user is the owner.
+// @Description Updates a specific organization by its ID, ensuring the authenticated user has access to it.
 // @Tags Organizations
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param orgID path string true "Organization ID"
 // @Param organization body models.UpdateOrganizationRequest true "Organization update details"
+// @Param If-Match header string true "ETag of the version being changed, or *"
 // @Success 200 {object} models.Organization "Organization updated successfully"
+// @Header 200 {string} ETag "Version of the organization"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
 // @Failure 409 {object} models.ErrorResponse "Conflict (if new name already exists)"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the organization has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /organizations/{orgID} [put]
 func UpdateOrganization(c *gin.Context) {
 	orgID := c.Param("orgID")
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req models.UpdateOrganizationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	updatedOrg, err := organizationService.UpdateOrganization(orgID, req)
+	updatedOrg, err := organizationService.UpdateOrganization(orgID, version, req)
 	if err != nil {
 		if strings.Contains(err.Error(), "organization name already exists") {
 			c.JSON(http.StatusConflict, models.ErrorResponse{Message: err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to update organization: " + err.Error()})
 		return
 	}
 
+	setETag(c, updatedOrg.Version)
 	c.JSON(http.StatusOK, updatedOrg)
 }
 
 // DeleteOrganization handles deleting an organization.
 // @Summary Delete an organization
-// @Description Deletes a specific organization by its ID, ensuring the authenticated user is the owner.
+// @Description Deletes a specific organization by its ID, ensuring the authenticated user has access to it. Everything beneath it, and its Casbin policies, are removed as well.
 // @Tags Organizations
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
-// @Success 204 "No Content"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.DeletionSummary "Counts of everything removed"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the organization has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /organizations/{orgID} [delete]
 func DeleteOrganization(c *gin.Context) {
+	userID, _ := c.Get("userID")
 	orgID := c.Param("orgID")
 
-	err := organizationService.DeleteOrganization(orgID)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	summary, err := organizationService.DeleteOrganization(orgID, version, userID.(string))
 	if err != nil {
 		if strings.Contains(err.Error(), "organization not found or already deleted") {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to delete organization: " + err.Error()})
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	c.JSON(http.StatusOK, summary)
 }
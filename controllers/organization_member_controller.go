@@ -0,0 +1,258 @@
+package controllers
+
+import (
+	"kanban-app/api/models"
+	"kanban-app/api/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var organizationMemberService *services.OrganizationMemberService
+
+func init() {
+	organizationMemberService = services.NewOrganizationMemberService()
+}
+
+// InviteMember handles inviting a user to an organization.
+// @Summary Invite a member
+// @Description Invites an existing user, identified by email or username, to join the organization with the given role. Requires the admin role.
+// @Tags Members
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param invitation body models.InviteMemberRequest true "Invitation details"
+// @Success 201 {object} models.OrganizationMember "Invitation created successfully"
+// @Header 201 {string} ETag "Version of the membership"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found (if user does not exist)"
+// @Failure 409 {object} models.ErrorResponse "Conflict (if user is already a member or invited)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/members [post]
+func InviteMember(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	orgID := c.Param("orgID")
+
+	var req models.InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	member, err := organizationMemberService.InviteMember(orgID, userID.(string), req)
+	if err != nil {
+		if strings.Contains(err.Error(), "user not found") {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "already a member") || strings.Contains(err.Error(), "pending invitation") {
+			c.JSON(http.StatusConflict, models.ErrorResponse{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to invite member: " + err.Error()})
+		return
+	}
+
+	setETag(c, member.Version)
+	c.JSON(http.StatusCreated, member)
+}
+
+// GetMembers handles retrieving the members of an organization.
+// @Summary Get organization members
+// @Description Retrieves all members and pending invitations of an organization.
+// @Tags Members
+// @Security ApiKeyAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Success 200 {array} models.OrganizationMember "List of members"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/members [get]
+func GetMembers(c *gin.Context) {
+	orgID := c.Param("orgID")
+
+	members, err := organizationMemberService.GetMembers(orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve members: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// UpdateMemberRole handles changing the role of an organization member.
+// @Summary Update a member's role
+// @Description Changes the role of a member. Requires the admin role; only owners may grant or revoke the owner role.
+// @Tags Members
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param memberID path string true "Member ID"
+// @Param role body models.UpdateMemberRoleRequest true "New role"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.OrganizationMember "Member updated successfully"
+// @Header 200 {string} ETag "Version of the membership"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict (if the last owner would be removed)"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the membership has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/members/{memberID} [put]
+func UpdateMemberRole(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	orgID := c.Param("orgID")
+	memberID := c.Param("memberID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	member, err := organizationMemberService.UpdateMemberRole(orgID, memberID, version, userID.(string), req.Role)
+	if err != nil {
+		handleMemberError(c, "Failed to update member: ", err)
+		return
+	}
+
+	setETag(c, member.Version)
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember handles removing a member from an organization.
+// @Summary Remove a member
+// @Description Removes a member or revokes a pending invitation, together with any roles granted to the user directly on the organization's projects and boards. Requires the admin role.
+// @Tags Members
+// @Security ApiKeyAuth
+// @Param orgID path string true "Organization ID"
+// @Param memberID path string true "Member ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict (if the last owner would be removed)"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the membership has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/members/{memberID} [delete]
+func RemoveMember(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	orgID := c.Param("orgID")
+	memberID := c.Param("memberID")
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := organizationMemberService.RemoveMember(orgID, memberID, version, userID.(string)); err != nil {
+		handleMemberError(c, "Failed to remove member: ", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetInvitations handles retrieving the pending invitations of the authenticated user.
+// @Summary Get my invitations
+// @Description Retrieves all pending organization invitations for the authenticated user.
+// @Tags Members
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} models.OrganizationMember "List of pending invitations"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /invitations [get]
+func GetInvitations(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	invitations, err := organizationMemberService.GetInvitationsForUser(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve invitations: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// AcceptInvitation handles accepting an organization invitation.
+// @Summary Accept an invitation
+// @Description Accepts a pending invitation addressed to the authenticated user and grants the invited role.
+// @Tags Members
+// @Security ApiKeyAuth
+// @Produce json
+// @Param invitationID path string true "Invitation ID"
+// @Success 200 {object} models.OrganizationMember "Invitation accepted"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict (if invitation is no longer pending)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /invitations/{invitationID}/accept [post]
+func AcceptInvitation(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	invitationID := c.Param("invitationID")
+
+	member, err := organizationMemberService.AcceptInvitation(invitationID, userID.(string))
+	if err != nil {
+		handleMemberError(c, "Failed to accept invitation: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// DeclineInvitation handles declining an organization invitation.
+// @Summary Decline an invitation
+// @Description Declines a pending invitation addressed to the authenticated user.
+// @Tags Members
+// @Security ApiKeyAuth
+// @Produce json
+// @Param invitationID path string true "Invitation ID"
+// @Success 200 {object} models.OrganizationMember "Invitation declined"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict (if invitation is no longer pending)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /invitations/{invitationID}/decline [post]
+func DeclineInvitation(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	invitationID := c.Param("invitationID")
+
+	member, err := organizationMemberService.DeclineInvitation(invitationID, userID.(string))
+	if err != nil {
+		handleMemberError(c, "Failed to decline invitation: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+func handleMemberError(c *gin.Context, prefix string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "member not found") || strings.Contains(err.Error(), "invitation not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "only owners"):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "at least one owner") || strings.Contains(err.Error(), "no longer pending"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "version mismatch"):
+		c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: prefix + err.Error()})
+	}
+}
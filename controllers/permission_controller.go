@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"kanban-app/api/models"
+	"kanban-app/api/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var permissionService *services.PermissionService
+
+func init() {
+	permissionService = services.NewPermissionService()
+}
+
+// GetBoardPermissions handles listing the roles granted directly on a board.
+// @Summary Get board permissions
+// @Description Lists the roles granted directly on a board. Access inherited from the organization or project is not included. Requires the admin action on the board.
+// @Tags Boards
+// @Security ApiKeyAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Param boardID path string true "Board ID"
+// @Success 200 {array} models.ResourcePermission "List of permissions"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/permissions [get]
+func GetBoardPermissions(c *gin.Context) {
+	boardID := c.Param("boardID")
+
+	permissions, err := permissionService.GetResourcePermissions(boardID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve permissions: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, permissions)
+}
+
+// GrantBoardPermission handles granting a user a role on a board.
+// @Summary Grant a board permission
+// @Description Grants a member of the board's organization a role on the board, e.g. viewer for read-only stakeholders, replacing any role granted directly before. The role is removed when the user leaves the organization. Requires the admin action on the board.
+// @Tags Boards
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Param boardID path string true "Board ID"
+// @Param userID path string true "User ID"
+// @Param permission body models.GrantPermissionRequest true "Role to grant"
+// @Success 200 {object} models.ResourcePermission "Permission granted"
+// @Failure 400 {object} models.ErrorResponse "Bad Request (if the user is not a member of the organization)"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/permissions/{userID} [put]
+func GrantBoardPermission(c *gin.Context) {
+	actorID, _ := c.Get("userID")
+	boardID := c.Param("boardID")
+	userID := c.Param("userID")
+
+	var req models.GrantPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	permission, err := permissionService.GrantRole(boardID, userID, req.Role, actorID.(string))
+	if err != nil {
+		if strings.Contains(err.Error(), "user not found") {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "not a member") {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to grant permission: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, permission)
+}
+
+// RevokeBoardPermission handles revoking a user's direct role on a board.
+// @Summary Revoke a board permission
+// @Description Revokes the roles granted directly to a user on a board. Requires the admin action on the board.
+// @Tags Boards
+// @Security ApiKeyAuth
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Param boardID path string true "Board ID"
+// @Param userID path string true "User ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/permissions/{userID} [delete]
+func RevokeBoardPermission(c *gin.Context) {
+	actorID, _ := c.Get("userID")
+	boardID := c.Param("boardID")
+	userID := c.Param("userID")
+
+	if err := permissionService.RevokeRoles(boardID, userID, actorID.(string)); err != nil {
+		if strings.Contains(err.Error(), "permission not found") {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to revoke permission: " + err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
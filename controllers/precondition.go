@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kanban-app/api/models"
+	"kanban-app/api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ifMatchVersion reads the version an update or delete applies to from the
+// If-Match header, which must hold the ETag the resource was read with, or
+// "*" to apply to whatever version is current. Otherwise it responds with 428
+// when the header is missing or 412 when it cannot match, and returns false.
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		c.JSON(http.StatusPreconditionRequired, models.ErrorResponse{Message: "If-Match header is required: send the ETag of the version being changed"})
+		return 0, false
+	}
+	if header == "*" {
+		return services.AnyVersion, true
+	}
+
+	version, err := strconv.Atoi(strings.Trim(header, `"`))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: "If-Match header does not match any version: " + header})
+		return 0, false
+	}
+	return version, true
+}
+
+// setETag tells the client which version of a resource it received.
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", `"`+strconv.Itoa(version)+`"`)
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kanban-app/api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		header      string
+		wantVersion int
+		wantOK      bool
+		wantStatus  int
+	}{
+		{"missing", "", 0, false, http.StatusPreconditionRequired},
+		{"blank", "  ", 0, false, http.StatusPreconditionRequired},
+		{"any version", "*", services.AnyVersion, true, http.StatusOK},
+		{"quoted ETag", `"3"`, 3, true, http.StatusOK},
+		{"bare version", "7", 7, true, http.StatusOK},
+		{"weak ETag", `W/"3"`, 0, false, http.StatusPreconditionFailed},
+		{"not a version", `"abc"`, 0, false, http.StatusPreconditionFailed},
+		{"zero", `"0"`, 0, false, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			version, ok := ifMatchVersion(c)
+			if ok != tt.wantOK || version != tt.wantVersion {
+				t.Errorf("ifMatchVersion = (%d, %v), want (%d, %v)", version, ok, tt.wantVersion, tt.wantOK)
+			}
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -17,7 +17,7 @@ func init() {
 
 // CreateProject handles creating a new project within an organization.
 // @Summary Create a new project
-// @Description Creates a new project within a specified organization. User must own the organization.
+// @Description Creates a new project within a specified organization. Requires write access to the organization.
 // @Tags Projects
 // @Security ApiKeyAuth
 // @Accept json
@@ -25,6 +25,7 @@ func init() {
 // @Param orgID path string true "Organization ID"
 // @Param project body models.CreateProjectRequest true "Project creation details"
 // @Success 201 {object} models.Project "Project created successfully"
+// @Header 201 {string} ETag "Version of the project"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden (if user does not own organization)"
@@ -52,12 +53,13 @@ func CreateProject(c *gin.Context) {
 		return
 	}
 
+	setETag(c, project.Version)
 	c.JSON(http.StatusCreated, project)
 }
 
 // GetProjects handles retrieving all projects for a specific organization.
 // @Summary Get all projects in an organization
-// @Description Retrieves all projects within a specified organization. User must own the organization.
+// @Description Retrieves all projects within a specified organization. Requires read access to the organization.
 // @Tags Projects
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
@@ -82,13 +84,14 @@ func GetProjects(c *gin.Context) {
 
 // GetProjectByID handles retrieving a specific project within an organization.
 // @Summary Get project by ID
-// @Description Retrieves a specific project by its ID within a specified organization. User must own the organization.
+// @Description Retrieves a specific project by its ID within a specified organization. Requires read access to the organization.
 // @Tags Projects
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
 // @Param projectID path string true "Project ID"
 // @Produce json
 // @Success 200 {object} models.Project "Project details"
+// @Header 200 {string} ETag "Version of the project"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
@@ -107,12 +110,13 @@ func GetProjectByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, project.Version)
 	c.JSON(http.StatusOK, project)
 }
 
 // UpdateProject handles updating an existing project within an organization.
 // @Summary Update a project
-// @Description Updates a specific project by its ID within a specified organization. User must own the organization.
+// @Description Updates a specific project by its ID within a specified organization. Requires write access to the organization.
 // @Tags Projects
 // @Security ApiKeyAuth
 // @Accept json
@@ -120,61 +124,88 @@ func GetProjectByID(c *gin.Context) {
 // @Param orgID path string true "Organization ID"
 // @Param projectID path string true "Project ID"
 // @Param project body models.UpdateProjectRequest true "Project update details"
+// @Param If-Match header string true "ETag of the version being changed, or *"
 // @Success 200 {object} models.Project "Project updated successfully"
+// @Header 200 {string} ETag "Version of the project"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
 // @Failure 409 {object} models.ErrorResponse "Conflict"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the project has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /organizations/{orgID}/projects/{projectID} [put]
 func UpdateProject(c *gin.Context) {
 	projectID := c.Param("projectID")
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var req models.UpdateProjectRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	updatedProject, err := projectService.UpdateProject(projectID, req)
+	updatedProject, err := projectService.UpdateProject(projectID, version, req)
 	if err != nil {
 		if strings.Contains(err.Error(), "project name already exists") {
 			c.JSON(http.StatusConflict, models.ErrorResponse{Message: err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to update project: " + err.Error()})
 		return
 	}
 
+	setETag(c, updatedProject.Version)
 	c.JSON(http.StatusOK, updatedProject)
 }
 
 // DeleteProject handles deleting a project within an organization.
 // @Summary Delete a project
-// @Description Deletes a specific project by its ID within a specified organization. User must own the organization.
+// @Description Deletes a specific project by its ID within a specified organization. Requires delete access to the organization. Everything beneath it, and its Casbin policies, are removed as well.
 // @Tags Projects
 // @Security ApiKeyAuth
 // @Param orgID path string true "Organization ID"
 // @Param projectID path string true "Project ID"
-// @Success 204 "No Content"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.DeletionSummary "Counts of everything removed"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized"
 // @Failure 403 {object} models.ErrorResponse "Forbidden"
 // @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the project has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
 // @Failure 500 {object} models.ErrorResponse "Internal Server Error"
 // @Router /organizations/{orgID}/projects/{projectID} [delete]
 func DeleteProject(c *gin.Context) {
+	userID, _ := c.Get("userID")
 	projectID := c.Param("projectID")
 
-	err := projectService.DeleteProject(projectID)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	summary, err := projectService.DeleteProject(projectID, version, userID.(string))
 	if err != nil {
 		if strings.Contains(err.Error(), "project not found or already deleted") {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "version mismatch") {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to delete project: " + err.Error()})
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	c.JSON(http.StatusOK, summary)
 }
\ No newline at end of file
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"kanban-app/api/models"
+	"kanban-app/api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var trashService *services.TrashService
+
+func init() {
+	trashService = services.NewTrashService()
+}
+
+// GetProjectTrash handles listing the boards in a project's trash.
+// @Summary Get project trash
+// @Description Retrieves the deleted boards of a project that can still be restored. Requires read access to the project.
+// @Tags Trash
+// @Security ApiKeyAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} models.Trash "Deleted boards"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/projects/{projectID}/trash [get]
+func GetProjectTrash(c *gin.Context) {
+	projectID := c.Param("projectID")
+
+	trash, err := trashService.GetProjectTrash(projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve trash: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, trash)
+}
+
+// GetBoardTrash handles listing the lists and cards in a board's trash.
+// @Summary Get board trash
+// @Description Retrieves the deleted lists and cards of a board that can still be restored. Requires read access to the board.
+// @Tags Trash
+// @Security ApiKeyAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Param boardID path string true "Board ID"
+// @Success 200 {object} models.Trash "Deleted lists and cards"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/trash [get]
+func GetBoardTrash(c *gin.Context) {
+	boardID := c.Param("boardID")
+
+	trash, err := trashService.GetBoardTrash(boardID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve trash: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, trash)
+}
+
+// RestoreBoard handles restoring a board from the trash.
+// @Summary Restore a board
+// @Description Restores a deleted board together with its lists and cards. Requires delete access to the board.
+// @Tags Trash
+// @Security ApiKeyAuth
+// @Produce json
+// @Param boardID path string true "Board ID"
+// @Success 200 {object} models.Board "Board restored successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /trash/boards/{boardID}/restore [post]
+func RestoreBoard(c *gin.Context) {
+	boardID := c.Param("boardID")
+
+	board, err := trashService.RestoreBoard(boardID)
+	if err != nil {
+		handleTrashError(c, "Failed to restore board: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+// RestoreList handles restoring a list from the trash.
+// @Summary Restore a list
+// @Description Restores a deleted list under its previous rank, so it returns between the same neighbours. The list comes back with the cards it held when deleted, which aren't checked against its WIP limit. Requires delete access to the list.
+// @Tags Trash
+// @Security ApiKeyAuth
+// @Produce json
+// @Param listID path string true "List ID"
+// @Success 200 {object} models.List "List restored successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict (if the board is also in the trash)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /trash/lists/{listID}/restore [post]
+func RestoreList(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	listID := c.Param("listID")
+
+	list, err := trashService.RestoreList(listID, userID.(string))
+	if err != nil {
+		handleTrashError(c, "Failed to restore list: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// RestoreCard handles restoring a card from the trash.
+// @Summary Restore a card
+// @Description Restores a deleted card under its previous rank, so it returns between the same neighbours. The card counts towards the list's WIP limit again: on a board with the strict policy a full list refuses it, and with the warn policy it is restored with wip_limit_exceeded set. Requires delete access to the card.
+// @Tags Trash
+// @Security ApiKeyAuth
+// @Produce json
+// @Param cardID path string true "Card ID"
+// @Success 200 {object} models.Card "Card restored successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 409 {object} models.ErrorResponse "Conflict (if the list is also in the trash or archived, or its WIP limit is reached)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /trash/cards/{cardID}/restore [post]
+func RestoreCard(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	cardID := c.Param("cardID")
+
+	card, err := trashService.RestoreCard(cardID, userID.(string))
+	if err != nil {
+		handleTrashError(c, "Failed to restore card: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, card)
+}
+
+func handleTrashError(c *gin.Context, prefix string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found in trash"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "is in the trash"), strings.Contains(err.Error(), "list is archived"),
+		strings.Contains(err.Error(), "WIP limit reached"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: prefix + err.Error()})
+	}
+}
@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"kanban-app/api/models"
+	"kanban-app/api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var webhookService *services.WebhookService
+
+func init() {
+	webhookService = services.NewWebhookService()
+}
+
+// CreateOrganizationWebhook handles registering a webhook for every board of an organization.
+// @Summary Create an organization webhook
+// @Description Registers a URL to receive the events of every board in the organization. Each delivery is a POST of the event as JSON, signed in the X-Webhook-Signature header as "sha256=" followed by the hex HMAC-SHA256 of the X-Webhook-Timestamp value, a dot and the body. The secret is generated unless given and is only returned here. Events filters the event types, e.g. ["card.*", "comment.added"]; leave it empty to receive everything. Requires the admin role.
+// @Tags Webhooks
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param webhook body models.CreateWebhookRequest true "Webhook details"
+// @Success 201 {object} models.CreatedWebhook "Webhook created successfully"
+// @Header 201 {string} ETag "Version of the webhook"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/webhooks [post]
+func CreateOrganizationWebhook(c *gin.Context) {
+	createWebhook(c, c.Param("orgID"), "")
+}
+
+// GetOrganizationWebhooks handles listing the organization-wide webhooks.
+// @Summary Get organization webhooks
+// @Description Retrieves the webhooks that receive the events of every board in the organization. Board webhooks are listed on their board. Requires the admin role.
+// @Tags Webhooks
+// @Security ApiKeyAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Success 200 {array} models.Webhook "List of webhooks"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/webhooks [get]
+func GetOrganizationWebhooks(c *gin.Context) {
+	webhooks, err := webhookService.GetOrganizationWebhooks(c.Param("orgID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve webhooks: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// CreateBoardWebhook handles registering a webhook for a single board.
+// @Summary Create a board webhook
+// @Description Registers a URL to receive the events of this board. Deliveries are signed the same way as organization webhooks. The secret is generated unless given and is only returned here. Requires the admin role on the board.
+// @Tags Webhooks
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Param boardID path string true "Board ID"
+// @Param webhook body models.CreateWebhookRequest true "Webhook details"
+// @Success 201 {object} models.CreatedWebhook "Webhook created successfully"
+// @Header 201 {string} ETag "Version of the webhook"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/webhooks [post]
+func CreateBoardWebhook(c *gin.Context) {
+	createWebhook(c, c.Param("orgID"), c.Param("boardID"))
+}
+
+// GetBoardWebhooks handles listing the webhooks of a single board.
+// @Summary Get board webhooks
+// @Description Retrieves the webhooks registered for this board, not including those of its organization. Requires the admin role on the board.
+// @Tags Webhooks
+// @Security ApiKeyAuth
+// @Produce json
+// @Param orgID path string true "Organization ID"
+// @Param projectID path string true "Project ID"
+// @Param boardID path string true "Board ID"
+// @Success 200 {array} models.Webhook "List of webhooks"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /organizations/{orgID}/projects/{projectID}/boards/{boardID}/webhooks [get]
+func GetBoardWebhooks(c *gin.Context) {
+	webhooks, err := webhookService.GetBoardWebhooks(c.Param("boardID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve webhooks: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+func createWebhook(c *gin.Context, orgID, boardID string) {
+	userID, _ := c.Get("userID")
+
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	webhook, err := webhookService.CreateWebhook(orgID, boardID, userID.(string), req)
+	if err != nil {
+		handleWebhookError(c, "Failed to create webhook: ", err)
+		return
+	}
+
+	setETag(c, webhook.Version)
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// GetWebhookByID handles retrieving a webhook by ID.
+// @Summary Get webhook by ID
+// @Description Retrieves a webhook. The secret is never returned. Requires the admin role on the webhook's board or organization.
+// @Tags Webhooks
+// @Security ApiKeyAuth
+// @Produce json
+// @Param webhookID path string true "Webhook ID"
+// @Success 200 {object} models.Webhook "Webhook details"
+// @Header 200 {string} ETag "Version of the webhook"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /webhooks/{webhookID} [get]
+func GetWebhookByID(c *gin.Context) {
+	webhook, err := webhookService.GetWebhookByID(c.Param("webhookID"))
+	if err != nil {
+		handleWebhookError(c, "Failed to retrieve webhook: ", err)
+		return
+	}
+
+	setETag(c, webhook.Version)
+	c.JSON(http.StatusOK, webhook)
+}
+
+// UpdateWebhook handles changing a webhook.
+// @Summary Update a webhook
+// @Description Changes only the fields present in the body: URL, secret, event filter, or whether the webhook is active. Pending deliveries of an inactive webhook are marked failed instead of sent. Requires the admin role on the webhook's board or organization.
+// @Tags Webhooks
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param webhookID path string true "Webhook ID"
+// @Param webhook body models.UpdateWebhookRequest true "Webhook update details"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 200 {object} models.Webhook "Webhook updated successfully"
+// @Header 200 {string} ETag "Version of the webhook"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the webhook has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /webhooks/{webhookID} [put]
+func UpdateWebhook(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	webhook, err := webhookService.UpdateWebhook(c.Param("webhookID"), version, req)
+	if err != nil {
+		handleWebhookError(c, "Failed to update webhook: ", err)
+		return
+	}
+
+	setETag(c, webhook.Version)
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook handles deleting a webhook.
+// @Summary Delete a webhook
+// @Description Deletes a webhook together with its delivery log. Requires the admin role on the webhook's board or organization.
+// @Tags Webhooks
+// @Security ApiKeyAuth
+// @Param webhookID path string true "Webhook ID"
+// @Param If-Match header string true "ETag of the version being changed, or *"
+// @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Not Found"
+// @Failure 412 {object} models.ErrorResponse "Precondition Failed (the webhook has changed since it was read)"
+// @Failure 428 {object} models.ErrorResponse "Precondition Required (the If-Match header is missing)"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /webhooks/{webhookID} [delete]
+func DeleteWebhook(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := webhookService.DeleteWebhook(c.Param("webhookID"), version); err != nil {
+		handleWebhookError(c, "Failed to delete webhook: ", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries handles retrieving a webhook's delivery log.
+// @Summary Get webhook deliveries
+// @Description Retrieves a page of the webhook's deliveries, newest first, with their status, number of attempts, the status code of the last attempt and when the next retry is due. Requires the admin role on the webhook's board or organization.
+// @Tags Webhooks
+// @Security ApiKeyAuth
+// @Produce json
+// @Param webhookID path string true "Webhook ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Deliveries per page (default 50, max 200)"
+// @Success 200 {object} models.WebhookDeliveryPage "Page of deliveries"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 500 {object} models.ErrorResponse "Internal Server Error"
+// @Router /webhooks/{webhookID}/deliveries [get]
+func GetWebhookDeliveries(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	page, err := webhookService.GetDeliveries(c.Param("webhookID"), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Failed to retrieve deliveries: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func handleWebhookError(c *gin.Context, prefix string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "unknown event type"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Message: err.Error()})
+	case strings.Contains(err.Error(), "version mismatch"):
+		c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: prefix + err.Error()})
+	}
+}
@@ -25,7 +25,7 @@ func ConnectDatabase() {
 
 	log.Println("Database connection established to kanban.db")
 
-	err = db.AutoMigrate(&models.User{}, &models.Organization{}, &models.Project{}, &models.Board{}, &models.List{}, &models.Card{}, &models.Label{}, &models.Comment{}, &models.Attachment{})
+	err = db.AutoMigrate(&models.User{}, &models.Organization{}, &models.Project{}, &models.Board{}, &models.List{}, &models.Card{}, &models.Label{}, &models.Comment{}, &models.Attachment{}, &models.OrganizationMember{}, &models.Checklist{}, &models.ChecklistItem{}, &models.Activity{}, &models.AuditEntry{}, &models.Webhook{}, &models.WebhookDelivery{}, &models.ListRule{}, &models.ListRuleRun{}, &models.Automation{}, &models.AutomationExecution{}, &models.CardListStay{})
 	if err != nil {
 		log.Fatalf("Failed to auto-migrate database schema: %v", err)
 	}
@@ -46,4 +46,24 @@ func ConnectDatabase() {
 
 	Enforcer = enforcer
 	log.Println("Casbin enforcer initialized")
+
+	if err := seedRoleHierarchy(); err != nil {
+		log.Fatalf("Failed to seed casbin roles: %v", err)
+	}
+
+	if err := migrateOrganizationOwners(); err != nil {
+		log.Fatalf("Failed to migrate organization owners: %v", err)
+	}
+
+	if err := migrateResourceHierarchy(); err != nil {
+		log.Fatalf("Failed to migrate resource hierarchy: %v", err)
+	}
+
+	if err := migrateLabelScopes(); err != nil {
+		log.Fatalf("Failed to migrate label scopes: %v", err)
+	}
+
+	if err := migrateCardListStays(); err != nil {
+		log.Fatalf("Failed to migrate card list stays: %v", err)
+	}
 }
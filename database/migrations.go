@@ -0,0 +1,261 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"kanban-app/api/models"
+)
+
+// roleHierarchy lists the Casbin grouping rules that make a higher role imply
+// every role below it, e.g. an owner may do anything a viewer can, and that
+// map each role onto the actions it unlocks. The admin role and the admin
+// action share a name, so admins are implicitly granted the admin action;
+// only owners are granted the own action.
+var roleHierarchy = [][]string{
+	{models.RoleOwner, models.RoleAdmin},
+	{models.RoleAdmin, models.RoleMember},
+	{models.RoleMember, models.RoleViewer},
+	{models.RoleViewer, models.ActionRead},
+	{models.RoleMember, models.ActionWrite},
+	{models.RoleAdmin, models.ActionDelete},
+	{models.RoleOwner, models.ActionOwn},
+}
+
+func seedRoleHierarchy() error {
+	for _, rule := range roleHierarchy {
+		if _, err := Enforcer.AddGroupingPolicy(rule[0], rule[1]); err != nil {
+			return fmt.Errorf("failed to seed role %s -> %s: %w", rule[0], rule[1], err)
+		}
+	}
+	return nil
+}
+
+// migrateOrganizationOwners creates an accepted owner membership for every
+// organization created before memberships existed.
+func migrateOrganizationOwners() error {
+	var orgs []models.Organization
+	err := DB.Where("id NOT IN (?)", DB.Model(&models.OrganizationMember{}).Select("organization_id").Where("user_id = organizations.owner_id")).Find(&orgs).Error
+	if err != nil {
+		return fmt.Errorf("failed to find organizations without owner membership: %w", err)
+	}
+
+	for _, org := range orgs {
+		member := models.OrganizationMember{
+			ID:             uuid.New().String(),
+			OrganizationID: org.ID,
+			UserID:         org.OwnerID,
+			Role:           models.RoleOwner,
+			Status:         models.MemberStatusAccepted,
+			InvitedBy:      org.OwnerID,
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		}
+		if err := DB.Create(&member).Error; err != nil {
+			return fmt.Errorf("failed to create owner membership for organization %s: %w", org.ID, err)
+		}
+		log.Printf("Migrated owner membership for organization %s\n", org.ID)
+	}
+	return nil
+}
+
+// resourceParents returns a child -> parent link for every project, board,
+// list and card, mirroring the foreign keys of the hierarchy.
+func resourceParents() ([][]string, error) {
+	hierarchy := []struct{ table, parentColumn string }{
+		{"projects", "organization_id"},
+		{"boards", "project_id"},
+		{"lists", "board_id"},
+		{"cards", "list_id"},
+	}
+
+	var links [][]string
+	for _, level := range hierarchy {
+		rows, err := DB.Table(level.table).Select("id, " + level.parentColumn).Rows()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", level.table, err)
+		}
+		for rows.Next() {
+			var child, parent string
+			if err := rows.Scan(&child, &parent); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s: %w", level.table, err)
+			}
+			links = append(links, []string{child, parent})
+		}
+		rows.Close()
+	}
+	return links, nil
+}
+
+// migrateResourceHierarchy links existing resources to their parents and drops
+// the per-object policies that the hierarchy now makes redundant. Policies that
+// grant something the subject would not inherit from an ancestor are kept.
+func migrateResourceHierarchy() error {
+	links, err := resourceParents()
+	if err != nil {
+		return err
+	}
+
+	parents := make(map[string]string, len(links))
+	added := 0
+	for _, link := range links {
+		parents[link[0]] = link[1]
+		exists, err := Enforcer.HasNamedGroupingPolicy("g2", link[0], link[1])
+		if err != nil {
+			return fmt.Errorf("failed to check resource link: %w", err)
+		}
+		if exists {
+			continue
+		}
+		if _, err := Enforcer.AddNamedGroupingPolicy("g2", link[0], link[1]); err != nil {
+			return fmt.Errorf("failed to link %s to %s: %w", link[0], link[1], err)
+		}
+		added++
+	}
+
+	policies, err := Enforcer.GetPolicy()
+	if err != nil {
+		return fmt.Errorf("failed to load policies: %w", err)
+	}
+
+	removed := 0
+	for _, policy := range policies {
+		sub, obj, act := policy[0], policy[1], policy[2]
+		parent, ok := parents[obj]
+		if !ok {
+			continue
+		}
+		inherited, err := Enforcer.Enforce(sub, parent, act)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate policy on %s: %w", parent, err)
+		}
+		if !inherited {
+			continue
+		}
+		if _, err := Enforcer.RemovePolicy(sub, obj, act); err != nil {
+			return fmt.Errorf("failed to remove redundant policy on %s: %w", obj, err)
+		}
+		removed++
+	}
+
+	if added > 0 || removed > 0 {
+		log.Printf("Migrated resource hierarchy: %d links added, %d redundant policies removed\n", added, removed)
+	}
+	return nil
+}
+
+// migrateLabelScopes assigns labels created while labels were global to the
+// organizations that use them. A label used in several organizations is copied
+// into each one and their cards are pointed at their own copy; an unused label
+// is copied into every organization, so nobody loses a label they could see.
+func migrateLabelScopes() error {
+	if err := DB.Exec("UPDATE labels SET board_id = '' WHERE board_id IS NULL").Error; err != nil {
+		return fmt.Errorf("failed to normalize label boards: %w", err)
+	}
+
+	var labels []models.Label
+	if err := DB.Where("organization_id IS NULL OR organization_id = ''").Find(&labels).Error; err != nil {
+		return fmt.Errorf("failed to find unscoped labels: %w", err)
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+
+	var allOrgIDs []string
+	if err := DB.Model(&models.Organization{}).Order("created_at ASC").Pluck("id", &allOrgIDs).Error; err != nil {
+		return fmt.Errorf("failed to read organizations: %w", err)
+	}
+
+	const cardsInOrganization = `SELECT cards.id FROM cards
+		JOIN lists ON lists.id = cards.list_id
+		JOIN boards ON boards.id = lists.board_id
+		JOIN projects ON projects.id = boards.project_id
+		WHERE projects.organization_id = ?`
+
+	copies := 0
+	for _, label := range labels {
+		var orgIDs []string
+		err := DB.Raw(`SELECT DISTINCT projects.organization_id FROM card_labels
+			JOIN cards ON cards.id = card_labels.card_id
+			JOIN lists ON lists.id = cards.list_id
+			JOIN boards ON boards.id = lists.board_id
+			JOIN projects ON projects.id = boards.project_id
+			WHERE card_labels.label_id = ?
+			ORDER BY projects.organization_id`, label.ID).Scan(&orgIDs).Error
+		if err != nil {
+			return fmt.Errorf("failed to find organizations using label %s: %w", label.ID, err)
+		}
+		if len(orgIDs) == 0 {
+			orgIDs = allOrgIDs
+		}
+		if len(orgIDs) == 0 {
+			log.Printf("WARNING: label %s is not used by any organization and was left unscoped\n", label.ID)
+			continue
+		}
+
+		scoped := map[string]string{label.ID: orgIDs[0]}
+		err = DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.Label{}).Where("id = ?", label.ID).Update("organization_id", orgIDs[0]).Error; err != nil {
+				return err
+			}
+			for _, orgID := range orgIDs[1:] {
+				labelCopy := label
+				labelCopy.ID = uuid.New().String()
+				labelCopy.OrganizationID = orgID
+				if err := tx.Create(&labelCopy).Error; err != nil {
+					return err
+				}
+				if err := tx.Exec("UPDATE card_labels SET label_id = ? WHERE label_id = ? AND card_id IN ("+cardsInOrganization+")", labelCopy.ID, label.ID, orgID).Error; err != nil {
+					return err
+				}
+				scoped[labelCopy.ID] = orgID
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scope label %s: %w", label.ID, err)
+		}
+
+		for labelID, orgID := range scoped {
+			if _, err := Enforcer.AddNamedGroupingPolicy("g2", labelID, orgID); err != nil {
+				return fmt.Errorf("failed to link label %s to %s: %w", labelID, orgID, err)
+			}
+		}
+		copies += len(scoped) - 1
+	}
+
+	log.Printf("Migrated label scopes: %d labels assigned to organizations, %d copies created\n", len(labels), copies)
+	return nil
+}
+
+// migrateCardListStays starts the list history of cards created before it
+// was recorded with a stay in their current list, from when they entered it
+// or, failing that, were created.
+func migrateCardListStays() error {
+	var cards []models.Card
+	err := DB.Unscoped().Where("NOT EXISTS (SELECT 1 FROM card_list_stays WHERE card_list_stays.card_id = cards.id)").Find(&cards).Error
+	if err != nil {
+		return fmt.Errorf("failed to find cards without list stays: %w", err)
+	}
+	if len(cards) == 0 {
+		return nil
+	}
+
+	stays := make([]models.CardListStay, len(cards))
+	for i, card := range cards {
+		stays[i] = models.CardListStay{ID: uuid.New().String(), CardID: card.ID, ListID: card.ListID, EnteredAt: card.CreatedAt}
+		if card.ListEnteredAt != nil {
+			stays[i].EnteredAt = *card.ListEnteredAt
+		}
+	}
+	if err := DB.CreateInBatches(&stays, 500).Error; err != nil {
+		return fmt.Errorf("failed to create list stays: %w", err)
+	}
+
+	log.Printf("Migrated card list stays: %d cards\n", len(cards))
+	return nil
+}
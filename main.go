@@ -22,6 +22,8 @@
 // @tag.description "User login and registration"
 // @tag.name Organizations
 // @tag.description "Operations related to organizations"
+// @tag.name Members
+// @tag.description "Organization membership and invitations"
 // @tag.name Projects
 // @tag.description "Operations related to projects within organizations"
 // @tag.name Boards
@@ -30,6 +32,18 @@
 // @tag.description "Operations related to lists (columns) within boards"
 // @tag.name Cards
 // @tag.description "Operations related to cards (tasks) within lists"
+// @tag.name Checklists
+// @tag.description "Checklists and their items on cards"
+// @tag.name Labels
+// @tag.description "Organization and board labels"
+// @tag.name Activity
+// @tag.description "History of changes to cards"
+// @tag.name Audit
+// @tag.description "Organization audit log of security-relevant actions"
+// @tag.name Webhooks
+// @tag.description "Signed delivery of board events to external URLs"
+// @tag.name Trash
+// @tag.description "Restoring deleted boards, lists and cards"
 package main
 
 import (
@@ -37,8 +51,12 @@ import (
 	"kanban-app/api/database"
 	_ "kanban-app/api/docs"
 	"kanban-app/api/middlewares"
+	"kanban-app/api/models"
+	"kanban-app/api/services"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -53,6 +71,24 @@ func main() {
 	}
 
 	database.ConnectDatabase()
+	if err := services.NewRankService().MigratePositions(); err != nil {
+		log.Fatalf("Failed to migrate positions: %v", err)
+	}
+
+	retentionDays := 30
+	if value := os.Getenv("TRASH_RETENTION_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 {
+			log.Printf("WARNING: invalid TRASH_RETENTION_DAYS %q. Using the default of %d days.\n", value, retentionDays)
+		} else {
+			retentionDays = days
+		}
+	}
+	services.NewTrashService().StartPurgeJob(time.Hour, time.Duration(retentionDays)*24*time.Hour)
+	services.NewWebhookService().StartDispatcher(5 * time.Second)
+	services.NewRankService().StartRebalanceJob(time.Hour)
+	services.NewListRuleService().StartScheduler(15 * time.Minute)
+	services.NewAutomationService().StartScheduler(time.Minute)
 
 	router := gin.Default()
 
@@ -70,107 +106,269 @@ func main() {
 		authenticated.POST("/organizations", controllers.CreateOrganization)
 		authenticated.GET("/organizations", controllers.GetOrganizations)
 		orgRoutes := authenticated.Group("/organizations/:orgID")
-		orgRoutes.Use(middlewares.CasbinMiddleware("orgID", "owner"))
 		{
-			orgRoutes.GET("", controllers.GetOrganizationByID)
-			orgRoutes.PUT("", controllers.UpdateOrganization)
-			orgRoutes.DELETE("", controllers.DeleteOrganization)
+			orgRoutes.GET("", middlewares.CasbinMiddleware("orgID"), controllers.GetOrganizationByID)
+			orgRoutes.PUT("", middlewares.CasbinActionMiddleware("orgID", models.ActionAdmin), controllers.UpdateOrganization)
+			orgRoutes.DELETE("", middlewares.CasbinActionMiddleware("orgID", models.ActionOwn), controllers.DeleteOrganization)
+		}
+
+		// organization audit log routes
+		auditRoutes := authenticated.Group("/organizations/:orgID/audit")
+		auditRoutes.Use(middlewares.CasbinActionMiddleware("orgID", models.ActionAdmin))
+		{
+			auditRoutes.GET("", controllers.GetAuditLog)
+			auditRoutes.GET("/export", controllers.ExportAuditLog)
+		}
+
+		// organization webhook routes, receiving the events of every board
+		orgWebhookRoutes := authenticated.Group("/organizations/:orgID/webhooks")
+		orgWebhookRoutes.Use(middlewares.CasbinActionMiddleware("orgID", models.ActionAdmin))
+		{
+			orgWebhookRoutes.GET("", controllers.GetOrganizationWebhooks)
+			orgWebhookRoutes.POST("", controllers.CreateOrganizationWebhook)
+		}
+
+		// organization member routes
+		memberRoutes := authenticated.Group("/organizations/:orgID/members")
+		{
+			memberRoutes.GET("", middlewares.CasbinMiddleware("orgID"), controllers.GetMembers)
+			memberRoutes.POST("", middlewares.CasbinActionMiddleware("orgID", models.ActionAdmin), controllers.InviteMember)
+			memberRoutes.PUT("/:memberID", middlewares.CasbinActionMiddleware("orgID", models.ActionAdmin), controllers.UpdateMemberRole)
+			memberRoutes.DELETE("/:memberID", middlewares.CasbinActionMiddleware("orgID", models.ActionAdmin), controllers.RemoveMember)
+		}
+
+		// organization label routes, shared by every board of the organization
+		orgLabelRoutes := authenticated.Group("/organizations/:orgID/labels")
+		orgLabelRoutes.Use(middlewares.CasbinMiddleware("orgID"))
+		{
+			orgLabelRoutes.GET("", controllers.GetOrganizationLabels)
+			orgLabelRoutes.POST("", controllers.CreateOrganizationLabel)
+		}
+
+		// invitation routes, scoped to the authenticated user rather than an organization
+		invitationRoutes := authenticated.Group("/invitations")
+		{
+			invitationRoutes.GET("", controllers.GetInvitations)
+			invitationRoutes.POST("/:invitationID/accept", controllers.AcceptInvitation)
+			invitationRoutes.POST("/:invitationID/decline", controllers.DeclineInvitation)
 		}
 
 		// project routes
 		projectRoutes := authenticated.Group("/organizations/:orgID/projects")
-		projectRoutes.Use(middlewares.CasbinMiddleware("orgID", "owner"))
+		projectRoutes.Use(middlewares.CasbinMiddleware("orgID"), middlewares.ResourcePathMiddleware())
 		{
 			projectRoutes.POST("", controllers.CreateProject)
 			projectRoutes.GET("", controllers.GetProjects)
 		}
 
 		projectDetailRoutes := authenticated.Group("/organizations/:orgID/projects/:projectID")
-		projectDetailRoutes.Use(middlewares.CasbinMiddleware("projectID", "owner"))
+		projectDetailRoutes.Use(middlewares.CasbinMiddleware("projectID"), middlewares.ResourcePathMiddleware())
 		{
 			projectDetailRoutes.GET("", controllers.GetProjectByID)
 			projectDetailRoutes.PUT("", controllers.UpdateProject)
 			projectDetailRoutes.DELETE("", controllers.DeleteProject)
+			projectDetailRoutes.GET("/trash", controllers.GetProjectTrash)
 		}
 
 		// Board routes (nested under projects)
 		boardRoutes := authenticated.Group("/organizations/:orgID/projects/:projectID/boards")
-		boardRoutes.Use(middlewares.CasbinMiddleware("projectID", "owner"))
+		boardRoutes.Use(middlewares.CasbinMiddleware("projectID"), middlewares.ResourcePathMiddleware())
 		{
 			boardRoutes.POST("", controllers.CreateBoard)
 			boardRoutes.GET("", controllers.GetBoards)
 		}
 
 		boardDetailRoutes := authenticated.Group("/organizations/:orgID/projects/:projectID/boards/:boardID")
-		boardDetailRoutes.Use(middlewares.CasbinMiddleware("boardID", "owner"))
+		boardDetailRoutes.Use(middlewares.CasbinMiddleware("boardID"), middlewares.ResourcePathMiddleware())
 		{
 			boardDetailRoutes.GET("", controllers.GetBoardByID)
 			boardDetailRoutes.PUT("", controllers.UpdateBoard)
 			boardDetailRoutes.DELETE("", controllers.DeleteBoard)
 			boardDetailRoutes.GET("/details", controllers.GetBoardDetails)
+			boardDetailRoutes.GET("/trash", controllers.GetBoardTrash)
+			boardDetailRoutes.GET("/labels", controllers.GetBoardLabels)
+			boardDetailRoutes.POST("/labels", controllers.CreateBoardLabel)
+			boardDetailRoutes.GET("/labels/:labelID/cards", controllers.GetCardsByLabel)
+		}
+
+		// Board permission routes, for granting access to a single board
+		boardPermissionRoutes := authenticated.Group("/organizations/:orgID/projects/:projectID/boards/:boardID/permissions")
+		boardPermissionRoutes.Use(middlewares.CasbinActionMiddleware("boardID", models.ActionAdmin), middlewares.ResourcePathMiddleware())
+		{
+			boardPermissionRoutes.GET("", controllers.GetBoardPermissions)
+			boardPermissionRoutes.PUT("/:userID", controllers.GrantBoardPermission)
+			boardPermissionRoutes.DELETE("/:userID", controllers.RevokeBoardPermission)
+		}
+
+		// Board webhook routes
+		boardWebhookRoutes := authenticated.Group("/organizations/:orgID/projects/:projectID/boards/:boardID/webhooks")
+		boardWebhookRoutes.Use(middlewares.CasbinActionMiddleware("boardID", models.ActionAdmin), middlewares.ResourcePathMiddleware())
+		{
+			boardWebhookRoutes.GET("", controllers.GetBoardWebhooks)
+			boardWebhookRoutes.POST("", controllers.CreateBoardWebhook)
 		}
 
 		// List routes (nested under boards)
 		listRoutes := authenticated.Group("/organizations/:orgID/projects/:projectID/boards/:boardID/lists")
-		listRoutes.Use(middlewares.CasbinMiddleware("boardID", "owner"))
+		listRoutes.Use(middlewares.CasbinMiddleware("boardID"), middlewares.ResourcePathMiddleware())
 		{
 			listRoutes.POST("", controllers.CreateList)
 			listRoutes.GET("", controllers.GetLists)
 		}
 
 		listDetailRoutes := authenticated.Group("/organizations/:orgID/projects/:projectID/boards/:boardID/lists/:listID")
-		listDetailRoutes.Use(middlewares.CasbinMiddleware("listID", "owner"))
+		listDetailRoutes.Use(middlewares.CasbinMiddleware("listID"), middlewares.ResourcePathMiddleware())
 		{
 			listDetailRoutes.GET("", controllers.GetListByID)
 			listDetailRoutes.PUT("", controllers.UpdateList)
 			listDetailRoutes.DELETE("", controllers.DeleteList)
+			listDetailRoutes.POST("/archive", controllers.ArchiveList)
+			listDetailRoutes.POST("/unarchive", controllers.UnarchiveList)
 		}
 
 		// Card routes (nested under lists)
 		cardRoutes := authenticated.Group("/organizations/:orgID/projects/:projectID/boards/:boardID/lists/:listID/cards")
-		cardRoutes.Use(middlewares.CasbinMiddleware("listID", "owner"))
+		cardRoutes.Use(middlewares.CasbinMiddleware("listID"), middlewares.ResourcePathMiddleware())
 		{
 			cardRoutes.POST("", controllers.CreateCard)
 			cardRoutes.GET("", controllers.GetCards)
+			cardRoutes.POST("/archive", controllers.ArchiveListCards)
 		}
 
 		cardDetailRoutes := authenticated.Group("/organizations/:orgID/projects/:projectID/boards/:boardID/lists/:listID/cards/:cardID")
-		cardDetailRoutes.Use(middlewares.CasbinMiddleware("cardID", "owner"))
+		cardDetailRoutes.Use(middlewares.CasbinMiddleware("cardID"), middlewares.ResourcePathMiddleware())
 		{
 			cardDetailRoutes.GET("", controllers.GetCardByID)
 			cardDetailRoutes.PUT("", controllers.UpdateCard)
+			cardDetailRoutes.PATCH("", controllers.PatchCard)
 			cardDetailRoutes.DELETE("", controllers.DeleteCard)
 		}
 
+		// Automation routes
+		automationRoutes := authenticated.Group("/boards/:boardID/automations")
+		automationRoutes.Use(middlewares.CasbinMiddleware("boardID"), middlewares.ResourcePathMiddleware())
+		{
+			automationRoutes.GET("", controllers.GetAutomations)
+			automationRoutes.POST("", controllers.CreateAutomation)
+			automationRoutes.GET("/:automationID", controllers.GetAutomationByID)
+			automationRoutes.PUT("/:automationID", controllers.UpdateAutomation)
+			automationRoutes.GET("/:automationID/executions", controllers.GetAutomationExecutions)
+		}
+		authenticated.DELETE("/boards/:boardID/automations/:automationID", middlewares.CasbinActionMiddleware("boardID", models.ActionWrite), middlewares.ResourcePathMiddleware(), controllers.DeleteAutomation)
+
+		// List rule routes, for automating housekeeping on a list
+		listRuleRoutes := authenticated.Group("/boards/:boardID/lists/:listID/rules")
+		listRuleRoutes.Use(middlewares.CasbinMiddleware("listID"), middlewares.ResourcePathMiddleware())
+		{
+			listRuleRoutes.GET("", controllers.GetListRules)
+			listRuleRoutes.POST("", controllers.CreateListRule)
+			listRuleRoutes.GET("/:ruleID", controllers.GetListRuleByID)
+			listRuleRoutes.PUT("/:ruleID", controllers.UpdateListRule)
+			listRuleRoutes.GET("/:ruleID/runs", controllers.GetListRuleRuns)
+		}
+		authenticated.DELETE("/boards/:boardID/lists/:listID/rules/:ruleID", middlewares.CasbinActionMiddleware("listID", models.ActionWrite), middlewares.ResourcePathMiddleware(), controllers.DeleteListRule)
+
 		// Comment routes (nested under cards)
 		commentRoutes := authenticated.Group("/cards/:cardID/comments")
-		commentRoutes.Use(middlewares.CasbinMiddleware("cardID", "owner"))
+		commentRoutes.Use(middlewares.CasbinMiddleware("cardID"), middlewares.ResourcePathMiddleware())
 		{
 			commentRoutes.POST("", controllers.CreateComment)
 		}
-		authenticated.DELETE("/cards/:cardID/comments/:commentID", middlewares.CasbinMiddleware("cardID", "owner"), controllers.DeleteComment)
+		authenticated.DELETE("/cards/:cardID/comments/:commentID", middlewares.CasbinActionMiddleware("cardID", models.ActionWrite), middlewares.ResourcePathMiddleware(), controllers.DeleteComment)
 
-		// Label routes
-		labelRoutes := authenticated.Group("/labels")
+		// Label routes, authorized through the board or organization that owns the label
+		labelRoutes := authenticated.Group("/labels/:labelID")
+		labelRoutes.Use(middlewares.CasbinMiddleware("labelID"))
 		{
-			labelRoutes.POST("", controllers.CreateLabel)
-			labelRoutes.GET("", controllers.GetAllLabels)
-			labelRoutes.GET("/:labelID", controllers.GetLabelByID)
-			labelRoutes.PUT("/:labelID", controllers.UpdateLabel)
-			labelRoutes.DELETE("/:labelID", controllers.DeleteLabel)
+			labelRoutes.GET("", controllers.GetLabelByID)
+			labelRoutes.PUT("", controllers.UpdateLabel)
+			labelRoutes.DELETE("", controllers.DeleteLabel)
+		}
+
+		// Webhook routes, authorized through the board or organization that owns the webhook
+		webhookRoutes := authenticated.Group("/webhooks/:webhookID")
+		webhookRoutes.Use(middlewares.CasbinActionMiddleware("webhookID", models.ActionAdmin))
+		{
+			webhookRoutes.GET("", controllers.GetWebhookByID)
+			webhookRoutes.PUT("", controllers.UpdateWebhook)
+			webhookRoutes.DELETE("", controllers.DeleteWebhook)
+			webhookRoutes.GET("/deliveries", controllers.GetWebhookDeliveries)
 		}
 
 		// Attachment routes (nested under cards)
 		attachmentRoutes := authenticated.Group("/cards/:cardID/attachments")
-		attachmentRoutes.Use(middlewares.CasbinMiddleware("cardID", "owner"))
+		attachmentRoutes.Use(middlewares.CasbinMiddleware("cardID"), middlewares.ResourcePathMiddleware())
 		{
 			attachmentRoutes.POST("", controllers.CreateAttachment)
 		}
-		authenticated.DELETE("/cards/:cardID/attachments/:attachmentID", middlewares.CasbinMiddleware("cardID", "owner"), controllers.DeleteAttachment)
+		authenticated.DELETE("/cards/:cardID/attachments/:attachmentID", middlewares.CasbinActionMiddleware("cardID", models.ActionWrite), middlewares.ResourcePathMiddleware(), controllers.DeleteAttachment)
+
+		// Checklist routes (nested under cards), deleting a checklist or item edits the card
+		checklistRoutes := authenticated.Group("/cards/:cardID/checklists")
+		checklistRoutes.Use(middlewares.CasbinMiddleware("cardID"), middlewares.ResourcePathMiddleware())
+		{
+			checklistRoutes.GET("", controllers.GetChecklists)
+			checklistRoutes.POST("", controllers.CreateChecklist)
+			checklistRoutes.PUT("/:checklistID", controllers.UpdateChecklist)
+			checklistRoutes.POST("/:checklistID/items", controllers.CreateChecklistItem)
+			checklistRoutes.PUT("/:checklistID/items/:itemID", controllers.UpdateChecklistItem)
+		}
+		authenticated.DELETE("/cards/:cardID/checklists/:checklistID", middlewares.CasbinActionMiddleware("cardID", models.ActionWrite), middlewares.ResourcePathMiddleware(), controllers.DeleteChecklist)
+		authenticated.DELETE("/cards/:cardID/checklists/:checklistID/items/:itemID", middlewares.CasbinActionMiddleware("cardID", models.ActionWrite), middlewares.ResourcePathMiddleware(), controllers.DeleteChecklistItem)
+
+		// Moving a card also checks write access to the destination list
+		authenticated.POST("/cards/:cardID/move", middlewares.CasbinActionMiddleware("cardID", models.ActionWrite), middlewares.ResourcePathMiddleware(), controllers.MoveCard)
+
+		authenticated.POST("/cards/:cardID/archive", middlewares.CasbinActionMiddleware("cardID", models.ActionWrite), middlewares.ResourcePathMiddleware(), controllers.ArchiveCard)
+		authenticated.POST("/cards/:cardID/unarchive", middlewares.CasbinActionMiddleware("cardID", models.ActionWrite), middlewares.ResourcePathMiddleware(), controllers.UnarchiveCard)
+
+		// Bulk card operations authorize each operation on its own card
+		authenticated.POST("/boards/:boardID/cards/bulk", middlewares.CasbinActionMiddleware("boardID", models.ActionRead), controllers.BulkUpdateCards)
+
+		// Card assignee and watcher routes
+		cardAssigneeRoutes := authenticated.Group("/cards/:cardID/assignees")
+		cardAssigneeRoutes.Use(middlewares.CasbinActionMiddleware("cardID", models.ActionWrite), middlewares.ResourcePathMiddleware())
+		{
+			cardAssigneeRoutes.POST("/:userID", controllers.AddCardAssignee)
+			cardAssigneeRoutes.DELETE("/:userID", controllers.RemoveCardAssignee)
+		}
+
+		cardWatcherRoutes := authenticated.Group("/cards/:cardID/watchers")
+		cardWatcherRoutes.Use(middlewares.CasbinActionMiddleware("cardID", models.ActionWrite), middlewares.ResourcePathMiddleware())
+		{
+			cardWatcherRoutes.POST("/:userID", controllers.AddCardWatcher)
+			cardWatcherRoutes.DELETE("/:userID", controllers.RemoveCardWatcher)
+		}
+
+		// Cards assigned to the authenticated user, across all boards
+		authenticated.GET("/me/cards", controllers.GetMyCards)
+
+		// Trash routes, restoring requires the same access as deleting
+		trashRoutes := authenticated.Group("/trash")
+		{
+			trashRoutes.POST("/boards/:boardID/restore", middlewares.CasbinActionMiddleware("boardID", models.ActionDelete), controllers.RestoreBoard)
+			trashRoutes.POST("/lists/:listID/restore", middlewares.CasbinActionMiddleware("listID", models.ActionDelete), controllers.RestoreList)
+			trashRoutes.POST("/cards/:cardID/restore", middlewares.CasbinActionMiddleware("cardID", models.ActionDelete), controllers.RestoreCard)
+		}
+
+		// Card-Label association routes, all of which edit the card
+		cardLabelRoutes := authenticated.Group("/cards/:cardID/labels")
+		cardLabelRoutes.Use(middlewares.CasbinActionMiddleware("cardID", models.ActionWrite), middlewares.ResourcePathMiddleware())
+		{
+			cardLabelRoutes.PUT("", controllers.SetCardLabels)
+			cardLabelRoutes.POST("/:labelID", controllers.AddLabelToCard)
+			cardLabelRoutes.DELETE("/:labelID", controllers.RemoveLabelFromCard)
+		}
+
+		// Activity routes, history is readable by anyone who can read the card or board
+		authenticated.GET("/cards/:cardID/activity", middlewares.CasbinMiddleware("cardID"), middlewares.ResourcePathMiddleware(), controllers.GetCardActivity)
+		authenticated.GET("/boards/:boardID/activity", middlewares.CasbinMiddleware("boardID"), middlewares.ResourcePathMiddleware(), controllers.GetBoardActivity)
+
+		// Board metrics, readable by anyone who can read the board
+		authenticated.GET("/boards/:boardID/metrics", middlewares.CasbinMiddleware("boardID"), middlewares.ResourcePathMiddleware(), controllers.GetBoardMetrics)
 
-		// Card-Label association routes // TODO implement these
-		//authenticated.POST("/cards/:cardID/labels/:labelID", middlewares.CasbinMiddleware("cardID", "owner"), controllers.AddLabelToCard)
-		//authenticated.DELETE("/cards/:cardID/labels/:labelID", middlewares.CasbinMiddleware("cardID", "owner"), controllers.RemoveLabelFromCard)
+		// Live board updates as Server-Sent Events
+		authenticated.GET("/boards/:boardID/events", middlewares.CasbinMiddleware("boardID"), middlewares.ResourcePathMiddleware(), controllers.StreamBoardEvents)
 
 	}
 
@@ -1,4 +1,3 @@
-
 package middlewares
 
 import (
@@ -10,7 +9,15 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func CasbinMiddleware(paramName, act string) gin.HandlerFunc {
+// CasbinMiddleware authorizes the request on the resource named by paramName,
+// deriving the required action (read, write or delete) from the HTTP method.
+func CasbinMiddleware(paramName string) gin.HandlerFunc {
+	return CasbinActionMiddleware(paramName, "")
+}
+
+// CasbinActionMiddleware authorizes the request on the resource named by
+// paramName for an explicit action or role, e.g. admin for member management.
+func CasbinActionMiddleware(paramName, act string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, exists := c.Get("userID")
 		if !exists {
@@ -20,8 +27,13 @@ func CasbinMiddleware(paramName, act string) gin.HandlerFunc {
 		}
 
 		obj := c.Param(paramName)
+		required := act
+		if required == "" {
+			required = auth.ActionForMethod(c.Request.Method)
+		}
 
-		can, err := auth.NewAuthorizationService().Enforce(userID.(string), obj, act)
+		authService := auth.NewAuthorizationService().WithActor(userID.(string))
+		can, err := authService.Enforce(userID.(string), obj, required)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Error checking authorization"})
 			c.Abort()
@@ -29,6 +41,12 @@ func CasbinMiddleware(paramName, act string) gin.HandlerFunc {
 		}
 
 		if !can {
+			authService.Audit(models.AuditEntry{
+				Action:     models.AuditAccessDenied,
+				ResourceID: obj,
+				Details:    map[string]any{"required": required, "method": c.Request.Method, "path": c.FullPath()},
+				IPAddress:  c.ClientIP(),
+			})
 			c.JSON(http.StatusForbidden, models.ErrorResponse{Message: "You are not authorized to perform this action"})
 			c.Abort()
 			return
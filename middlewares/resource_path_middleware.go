@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"kanban-app/api/models"
+	"kanban-app/api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResourcePathMiddleware checks that the IDs in a nested route belong to each
+// other, so a card can't be reached through another organization's URL, and
+// stores the resolved path in the context under "resourcePath".
+func ResourcePathMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ids := models.ResourcePath{
+			OrganizationID: c.Param("orgID"),
+			ProjectID:      c.Param("projectID"),
+			BoardID:        c.Param("boardID"),
+			ListID:         c.Param("listID"),
+			CardID:         c.Param("cardID"),
+			CommentID:      c.Param("commentID"),
+			AttachmentID:   c.Param("attachmentID"),
+			ChecklistID:    c.Param("checklistID"),
+			ItemID:         c.Param("itemID"),
+		}
+
+		path, err := services.NewHierarchyService().ResolvePath(ids)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				c.JSON(http.StatusNotFound, models.ErrorResponse{Message: err.Error()})
+			} else {
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{Message: "Error resolving resource path"})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set("resourcePath", path)
+		c.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Activity actions. Changes to a card's labels, assignees or watchers record
+// the whole set before and after the change.
+const (
+	ActivityCardCreated      = "card.created"
+	ActivityCardUpdated      = "card.updated"
+	ActivityCardMoved        = "card.moved"
+	ActivityCardDeleted      = "card.deleted"
+	ActivityCardRestored     = "card.restored"
+	ActivityCardArchived     = "card.archived"
+	ActivityCardUnarchived   = "card.unarchived"
+	ActivityLabelsChanged    = "card.labels_changed"
+	ActivityAssigneesChanged = "card.assignees_changed"
+	ActivityWatchersChanged  = "card.watchers_changed"
+	ActivityCommentAdded     = "comment.added"
+	ActivityAttachmentAdded  = "attachment.added"
+)
+
+// Activity is one entry in the history of a card. Before and After only hold
+// the fields the change touched.
+type Activity struct {
+	ID        string         `json:"id" gorm:"primaryKey"`
+	BoardID   string         `json:"board_id" gorm:"not null;index"`
+	CardID    string         `json:"card_id" gorm:"not null;index"`
+	ActorID   string         `json:"actor_id" gorm:"not null"`
+	Action    string         `json:"action" gorm:"not null"`
+	Before    map[string]any `json:"before,omitempty" gorm:"serializer:json"`
+	After     map[string]any `json:"after,omitempty" gorm:"serializer:json"`
+	CreatedAt time.Time      `json:"created_at" gorm:"not null;index"`
+
+	Actor *User `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+}
+
+type ActivityPage struct {
+	Activities []Activity `json:"activities"`
+	Page       int        `json:"page"`
+	PerPage    int        `json:"per_page"`
+	Total      int64      `json:"total"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// ArchiveQuery binds the include_archived query parameter. Archived lists and
+// cards are left out of board views and card listings unless it is true.
+type ArchiveQuery struct {
+	IncludeArchived bool `form:"include_archived"`
+}
+
+// ArchivedCards is the outcome of archiving every card in a list.
+type ArchivedCards struct {
+	ListID   string   `json:"list_id"`
+	Archived int      `json:"archived"`
+	CardIDs  []string `json:"card_ids"`
+}
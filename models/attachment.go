@@ -8,6 +8,7 @@ type Attachment struct {
 	FileName  string    `json:"file_name" gorm:"not null"`
 	FileURL   string    `json:"file_url" gorm:"not null"`
 	FileType  string    `json:"file_type"`
+	Version   int       `json:"version" gorm:"not null;default:1"`
 	CreatedAt time.Time `json:"created_at" gorm:"not null"`
 }
 
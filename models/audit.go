@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Audit actions.
+const (
+	AuditLoginSucceeded      = "auth.login_succeeded"
+	AuditLoginFailed         = "auth.login_failed"
+	AuditRegistered          = "auth.registered"
+	AuditPolicyAdded         = "policy.added"
+	AuditPolicyRemoved       = "policy.removed"
+	AuditOrganizationDeleted = "organization.deleted"
+	AuditProjectDeleted      = "project.deleted"
+	AuditAccessDenied        = "access.denied"
+)
+
+// AuditEntry is an append-only record of a security-relevant action. Entries
+// about an account rather than an organization, such as logins, have no
+// organization and appear in the audit log of every organization the account
+// belongs to.
+type AuditEntry struct {
+	ID             string         `json:"id" gorm:"primaryKey"`
+	OrganizationID string         `json:"organization_id,omitempty" gorm:"index"`
+	ActorID        string         `json:"actor_id,omitempty" gorm:"index"`
+	Action         string         `json:"action" gorm:"not null;index"`
+	ResourceID     string         `json:"resource_id,omitempty"`
+	Details        map[string]any `json:"details,omitempty" gorm:"serializer:json"`
+	IPAddress      string         `json:"ip_address,omitempty"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"not null;index"`
+}
+
+// AuditQuery filters the audit log. From is inclusive and To exclusive; both
+// are RFC 3339 timestamps.
+type AuditQuery struct {
+	PaginationQuery
+	ActorID string     `form:"actor_id" binding:"omitempty,uuid"`
+	Action  string     `form:"action"`
+	From    *time.Time `form:"from"`
+	To      *time.Time `form:"to"`
+}
+
+type AuditPage struct {
+	Entries []AuditEntry `json:"entries"`
+	Page    int          `json:"page"`
+	PerPage int          `json:"per_page"`
+	Total   int64        `json:"total"`
+}
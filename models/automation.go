@@ -0,0 +1,118 @@
+package models
+
+import "time"
+
+// Automation triggers. card_moved_into_list fires when a card enters
+// Trigger.ListID from another list, label_added when Trigger.LabelID is added
+// to a card, and due_date_passed when a card's due date goes by.
+const (
+	TriggerCardMovedIntoList = "card_moved_into_list"
+	TriggerLabelAdded        = "label_added"
+	TriggerDueDatePassed     = "due_date_passed"
+)
+
+// Automation actions, applied to the card that triggered the automation.
+const (
+	AutomationMoveToList = "move_to_list"
+	AutomationAddLabel   = "add_label"
+	AutomationSetDueDate = "set_due_date"
+	AutomationAddComment = "add_comment"
+)
+
+// Outcomes of an automation execution. An execution is skipped when it would
+// run the same automation on the same card again within one chain of
+// automations, or go deeper than the chain allows.
+const (
+	ExecutionSucceeded = "succeeded"
+	ExecutionFailed    = "failed"
+	ExecutionSkipped   = "skipped"
+)
+
+// Automation runs its actions on a card of its board whenever the trigger
+// fires and the card meets the conditions. Actions run in one transaction
+// on behalf of the user who created the automation, and may fire further
+// automations in turn.
+type Automation struct {
+	ID         string               `json:"id" gorm:"primaryKey"`
+	BoardID    string               `json:"board_id" gorm:"not null;index"`
+	Name       string               `json:"name" gorm:"not null"`
+	Trigger    AutomationTrigger    `json:"trigger" gorm:"serializer:json"`
+	Conditions AutomationConditions `json:"conditions" gorm:"serializer:json"`
+	Actions    []AutomationAction   `json:"actions" gorm:"serializer:json"`
+	Enabled    bool                 `json:"enabled" gorm:"not null"`
+	CreatedBy  string               `json:"created_by"`
+	CreatedAt  time.Time            `json:"created_at" gorm:"not null"`
+	UpdatedAt  time.Time            `json:"updated_at" gorm:"not null"`
+	Version    int                  `json:"version" gorm:"not null;default:1"`
+
+	// DueCheckedAt is when due dates were last checked for a due_date_passed
+	// trigger. Only due dates passed since then fire it.
+	DueCheckedAt *time.Time `json:"-"`
+}
+
+type AutomationTrigger struct {
+	Type    string `json:"type" binding:"required,oneof=card_moved_into_list label_added due_date_passed"`
+	ListID  string `json:"list_id,omitempty" binding:"omitempty,uuid"`
+	LabelID string `json:"label_id,omitempty" binding:"omitempty,uuid"`
+}
+
+// AutomationConditions must all hold for the card: it must carry every label
+// in LabelIDs and be assigned to every user in AssigneeIDs.
+type AutomationConditions struct {
+	LabelIDs    []string `json:"label_ids,omitempty" binding:"omitempty,dive,uuid"`
+	AssigneeIDs []string `json:"assignee_ids,omitempty" binding:"omitempty,dive,uuid"`
+}
+
+// AutomationAction is one change to the card. Which fields it reads depends
+// on Type: move_to_list takes ListID and Position, which is 1-based and
+// defaults to the end of the list, add_label LabelID, set_due_date
+// DueInDays, counted from when the action runs (null clears the due date),
+// and add_comment Text.
+type AutomationAction struct {
+	Type      string `json:"type" binding:"required,oneof=move_to_list add_label set_due_date add_comment"`
+	ListID    string `json:"list_id,omitempty" binding:"omitempty,uuid"`
+	Position  *int   `json:"position,omitempty" binding:"omitempty,min=1"`
+	LabelID   string `json:"label_id,omitempty" binding:"omitempty,uuid"`
+	DueInDays *int   `json:"due_in_days,omitempty" binding:"omitempty,min=0,max=3650"`
+	Text      string `json:"text,omitempty" binding:"omitempty,max=1000"`
+}
+
+// AutomationExecution records one time an automation fired on a card. Depth
+// is how many automations ran before it in the chain started by a user's
+// change or the scheduler.
+type AutomationExecution struct {
+	ID           string    `json:"id" gorm:"primaryKey"`
+	AutomationID string    `json:"automation_id" gorm:"not null;index"`
+	CardID       string    `json:"card_id" gorm:"not null"`
+	Trigger      string    `json:"trigger" gorm:"not null"`
+	Depth        int       `json:"depth"`
+	Status       string    `json:"status" gorm:"not null"`
+	Actions      []string  `json:"actions" gorm:"serializer:json"`
+	Error        string    `json:"error,omitempty"`
+	CreatedAt    time.Time `json:"created_at" gorm:"not null;index"`
+}
+
+type AutomationExecutionPage struct {
+	Executions []AutomationExecution `json:"executions"`
+	Page       int                   `json:"page"`
+	PerPage    int                   `json:"per_page"`
+	Total      int64                 `json:"total"`
+}
+
+type CreateAutomationRequest struct {
+	Name       string               `json:"name" binding:"required,min=1,max=100"`
+	Trigger    AutomationTrigger    `json:"trigger"`
+	Conditions AutomationConditions `json:"conditions"`
+	Actions    []AutomationAction   `json:"actions" binding:"required,min=1,max=10,dive"`
+	Enabled    *bool                `json:"enabled"`
+}
+
+// UpdateAutomationRequest changes only the fields present in the body. A
+// trigger, conditions or actions given replace the current ones.
+type UpdateAutomationRequest struct {
+	Name       *string               `json:"name" binding:"omitempty,min=1,max=100"`
+	Trigger    *AutomationTrigger    `json:"trigger"`
+	Conditions *AutomationConditions `json:"conditions"`
+	Actions    *[]AutomationAction   `json:"actions" binding:"omitempty,min=1,max=10,dive"`
+	Enabled    *bool                 `json:"enabled"`
+}
@@ -1,16 +1,32 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WIP policies of a board, deciding what happens to a card created in or
+// moved into a list already holding as many cards as its WIP limit: strict
+// refuses the card, warn lets it in and flags the response.
+const (
+	WIPPolicyStrict = "strict"
+	WIPPolicyWarn   = "warn"
+)
 
 type Board struct {
-	ID          string    `json:"id" gorm:"primaryKey"`
-	ProjectID   string    `json:"project_id" gorm:"not null"`
-	Name        string    `json:"name" gorm:"not null"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at" gorm:"not null"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"not null"`
+	ID          string         `json:"id" gorm:"primaryKey"`
+	ProjectID   string         `json:"project_id" gorm:"not null"`
+	Name        string         `json:"name" gorm:"not null"`
+	Description string         `json:"description"`
+	WIPPolicy   string         `json:"wip_policy" gorm:"column:wip_policy;not null;default:strict"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"not null"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"not null"`
+	Version     int            `json:"version" gorm:"not null;default:1"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 
 	Project Project `json:"-" gorm:"foreignKey:ProjectID"`
+	Lists   []*List `json:"lists" gorm:"foreignKey:BoardID"`
 }
 
 type CreateBoardRequest struct {
@@ -21,4 +37,5 @@ type CreateBoardRequest struct {
 type UpdateBoardRequest struct {
 	Name        string `json:"name" binding:"omitempty,min=3,max=100"`
 	Description string `json:"description" binding:"omitempty,max=500"`
+	WIPPolicy   string `json:"wip_policy" binding:"omitempty,oneof=strict warn"`
 }
@@ -0,0 +1,88 @@
+package models
+
+import "time"
+
+// Board event types. Payloads of created, updated and restored events are the
+// resource itself; moved events carry the new rank to reorder by and deleted
+// events the IDs a client needs to patch its copy of the board.
+const (
+	EventListCreated          = "list.created"
+	EventListUpdated          = "list.updated"
+	EventListMoved            = "list.moved"
+	EventListDeleted          = "list.deleted"
+	EventListRestored         = "list.restored"
+	EventListArchived         = "list.archived"
+	EventListUnarchived       = "list.unarchived"
+	EventCardCreated          = "card.created"
+	EventCardUpdated          = "card.updated"
+	EventCardMoved            = "card.moved"
+	EventCardDeleted          = "card.deleted"
+	EventCardRestored         = "card.restored"
+	EventCardArchived         = "card.archived"
+	EventCardUnarchived       = "card.unarchived"
+	EventCardLabelsChanged    = "card.labels_changed"
+	EventCardAssigneesChanged = "card.assignees_changed"
+	EventCardWatchersChanged  = "card.watchers_changed"
+	EventCommentAdded         = "comment.added"
+	EventCommentDeleted       = "comment.deleted"
+	EventAttachmentAdded      = "attachment.added"
+	EventAttachmentDeleted    = "attachment.deleted"
+	EventLabelCreated         = "label.created"
+	EventLabelUpdated         = "label.updated"
+	EventLabelDeleted         = "label.deleted"
+)
+
+// BoardEventTypes lists every board event type, e.g. to validate webhook
+// event filters.
+var BoardEventTypes = []string{
+	EventListCreated, EventListUpdated, EventListMoved, EventListDeleted, EventListRestored, EventListArchived, EventListUnarchived,
+	EventCardCreated, EventCardUpdated, EventCardMoved, EventCardDeleted, EventCardRestored, EventCardArchived, EventCardUnarchived,
+	EventCardLabelsChanged, EventCardAssigneesChanged, EventCardWatchersChanged,
+	EventCommentAdded, EventCommentDeleted, EventAttachmentAdded, EventAttachmentDeleted,
+	EventLabelCreated, EventLabelUpdated, EventLabelDeleted,
+}
+
+// BoardEvent is a change to a board delivered to its subscribers.
+type BoardEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	BoardID   string    `json:"board_id"`
+	ActorID   string    `json:"actor_id,omitempty"`
+	Data      any       `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListMovedEvent struct {
+	ListID string `json:"list_id"`
+	Rank   string `json:"rank"`
+}
+
+type ListDeletedEvent struct {
+	ListID string `json:"list_id"`
+}
+
+type CardMovedEvent struct {
+	CardID     string `json:"card_id"`
+	FromListID string `json:"from_list_id"`
+	ToListID   string `json:"to_list_id"`
+	Rank       string `json:"rank"`
+}
+
+type CardDeletedEvent struct {
+	CardID string `json:"card_id"`
+	ListID string `json:"list_id"`
+}
+
+type CommentDeletedEvent struct {
+	CommentID string `json:"comment_id"`
+	CardID    string `json:"card_id"`
+}
+
+type AttachmentDeletedEvent struct {
+	AttachmentID string `json:"attachment_id"`
+	CardID       string `json:"card_id"`
+}
+
+type LabelDeletedEvent struct {
+	LabelID string `json:"label_id"`
+}
@@ -2,21 +2,40 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Card struct {
 	ID          string    `json:"id" gorm:"primaryKey"`
-	ListID      string    `json:"list_id" gorm:"not null"`
+	ListID      string    `json:"list_id" gorm:"not null;index:idx_card_rank"`
 	Title       string    `json:"title" gorm:"not null"`
 	Description string    `json:"description"`
 	Notes       string    `json:"notes"`
-	Position    int       `json:"position" gorm:"not null"`
+	Rank        string    `json:"rank" gorm:"not null;default:'';index:idx_card_rank"`
 	DueDate     *time.Time `json:"due_date"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty" gorm:"index"`
+	// ListEnteredAt is when the card entered its current list. Cards created
+	// before it was tracked have none.
+	ListEnteredAt *time.Time `json:"list_entered_at,omitempty"`
 	CreatedAt   time.Time `json:"created_at" gorm:"not null"`
 	UpdatedAt   time.Time `json:"updated_at" gorm:"not null"`
+	Version     int       `json:"version" gorm:"not null;default:1"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	// CommentCount is only filled in by board snapshots, which omit the comments themselves.
+	CommentCount *int64 `json:"comment_count,omitempty" gorm:"->;-:migration"`
+	// ChecklistProgress is filled in by board views for cards with checklists.
+	ChecklistProgress *ChecklistProgress `json:"checklist_progress,omitempty" gorm:"-"`
+	// WIPLimitExceeded is set on a card just created in, moved into, unarchived
+	// or restored to a list that it took past its WIP limit, which the board's
+	// warn policy allows.
+	WIPLimitExceeded bool `json:"wip_limit_exceeded,omitempty" gorm:"-"`
 
 	List        List         `json:"-" gorm:"foreignKey:ListID"`
 	Labels      []*Label     `json:"labels" gorm:"many2many:card_labels;"`
+	Assignees   []*User      `json:"assignees" gorm:"many2many:card_assignees;"`
+	Watchers    []*User      `json:"watchers" gorm:"many2many:card_watchers;"`
 	Comments    []*Comment   `json:"comments" gorm:"foreignKey:CardID"`
 	Attachments []*Attachment `json:"attachments" gorm:"foreignKey:CardID"`
 }
@@ -27,10 +46,29 @@ type CreateCardRequest struct {
 	DueDate     *time.Time `json:"due_date" binding:"omitempty"`
 }
 
+// UpdateCardRequest changes only the fields present in the body. Description,
+// notes and due date are cleared by sending null; title cannot be cleared.
+// Position is 1-based within the card's list, or within ListID if given.
 type UpdateCardRequest struct {
-	Title       string     `json:"title" binding:"omitempty,min=1,max=200"`
-	Description string     `json:"description" binding:"omitempty,max=1000"`
-	Position    *int       `json:"position" binding:"omitempty"`
-	DueDate     *time.Time `json:"due_date" binding:"omitempty"`
-	ListID      string     `json:"list_id" binding:"omitempty,uuid"`
+	Title       Optional[string]    `json:"title" binding:"omitempty,min=1,max=200" swaggertype:"string"`
+	Description Optional[string]    `json:"description" binding:"omitempty,max=1000" swaggertype:"string"`
+	Notes       Optional[string]    `json:"notes" binding:"omitempty,max=5000" swaggertype:"string"`
+	DueDate     Optional[time.Time] `json:"due_date" swaggertype:"string" format:"date-time"`
+	Position    *int                `json:"position" binding:"omitempty"`
+	ListID      string              `json:"list_id" binding:"omitempty,uuid"`
+}
+
+// MoveCardRequest moves a card to a 1-based position in a list, or to the end
+// of the list when position is omitted.
+type MoveCardRequest struct {
+	ListID   string `json:"list_id" binding:"required,uuid"`
+	Position *int   `json:"position" binding:"omitempty,min=1"`
+}
+
+// CardMoveResult is a moved card with the new ordering of the list it entered
+// and, when it changed lists, of the list it left.
+type CardMoveResult struct {
+	Card     *Card `json:"card"`
+	FromList *List `json:"from_list,omitempty"`
+	ToList   *List `json:"to_list"`
 }
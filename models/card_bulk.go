@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Bulk card operations.
+const (
+	BulkOpMove       = "move"
+	BulkOpSetLabels  = "set_labels"
+	BulkOpSetDueDate = "set_due_date"
+	BulkOpAssign     = "assign"
+	BulkOpArchive    = "archive"
+	BulkOpDelete     = "delete"
+)
+
+// Outcomes of a bulk card operation. Operations run in one transaction, so
+// when one fails the ones before it are rolled back and the ones after it
+// are skipped.
+const (
+	BulkResultApplied    = "applied"
+	BulkResultFailed     = "failed"
+	BulkResultRolledBack = "rolled_back"
+	BulkResultSkipped    = "skipped"
+)
+
+// BulkCardOperation is one change in a bulk request. Which fields it reads
+// depends on Op: move takes ListID and Position like the move endpoint,
+// set_labels LabelIDs, set_due_date DueDate (null clears it) and assign
+// UserIDs, which replace the card's assignees. Version is the card's version
+// as read; leave it out to apply the operation to whatever is current.
+type BulkCardOperation struct {
+	Op       string              `json:"op" binding:"required,oneof=move set_labels set_due_date assign archive delete"`
+	CardID   string              `json:"card_id" binding:"required,uuid"`
+	Version  int                 `json:"version" binding:"omitempty,min=1"`
+	ListID   string              `json:"list_id" binding:"omitempty,uuid"`
+	Position *int                `json:"position" binding:"omitempty,min=1"`
+	LabelIDs []string            `json:"label_ids"`
+	DueDate  Optional[time.Time] `json:"due_date" swaggertype:"string" format:"date-time"`
+	UserIDs  []string            `json:"user_ids"`
+}
+
+type BulkCardRequest struct {
+	Operations []BulkCardOperation `json:"operations" binding:"required,min=1,max=100,dive"`
+}
+
+// BulkCardResult is the outcome of one operation, with the card as it was
+// left by the whole batch unless the operation deleted it.
+type BulkCardResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	CardID string `json:"card_id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Card   *Card  `json:"card,omitempty"`
+}
+
+type BulkCardResponse struct {
+	Applied bool             `json:"applied"`
+	Results []BulkCardResult `json:"results"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// CardListStay is a stretch of time a card spent in a list, from entering it
+// until leaving it. The stay in the card's current list has no ExitedAt.
+type CardListStay struct {
+	ID        string     `json:"id" gorm:"primaryKey"`
+	CardID    string     `json:"card_id" gorm:"not null;index"`
+	ListID    string     `json:"list_id" gorm:"not null;index"`
+	EnteredAt time.Time  `json:"entered_at" gorm:"not null"`
+	ExitedAt  *time.Time `json:"exited_at"`
+}
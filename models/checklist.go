@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+type Checklist struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	CardID    string    `json:"card_id" gorm:"not null;index"`
+	Title     string    `json:"title" gorm:"not null"`
+	Position  int       `json:"position" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"not null"`
+	Version   int       `json:"version" gorm:"not null;default:1"`
+
+	Items []*ChecklistItem `json:"items" gorm:"foreignKey:ChecklistID"`
+}
+
+type ChecklistItem struct {
+	ID          string     `json:"id" gorm:"primaryKey"`
+	ChecklistID string     `json:"checklist_id" gorm:"not null;index"`
+	Content     string     `json:"content" gorm:"not null"`
+	Position    int        `json:"position" gorm:"not null"`
+	Completed   bool       `json:"completed" gorm:"not null;default:false"`
+	CompletedAt *time.Time `json:"completed_at"`
+	AssigneeID  *string    `json:"assignee_id"`
+	DueDate     *time.Time `json:"due_date"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"not null"`
+	Version     int        `json:"version" gorm:"not null;default:1"`
+
+	Assignee *User `json:"assignee,omitempty" gorm:"foreignKey:AssigneeID"`
+}
+
+// ChecklistProgress summarizes the items of all checklists on a card.
+type ChecklistProgress struct {
+	Completed int64 `json:"completed"`
+	Total     int64 `json:"total"`
+}
+
+type CreateChecklistRequest struct {
+	Title string `json:"title" binding:"required,min=1,max=200"`
+}
+
+type UpdateChecklistRequest struct {
+	Title    string `json:"title" binding:"omitempty,min=1,max=200"`
+	Position *int   `json:"position" binding:"omitempty,min=1"`
+}
+
+type CreateChecklistItemRequest struct {
+	Content    string     `json:"content" binding:"required,min=1,max=500"`
+	AssigneeID *string    `json:"assignee_id" binding:"omitempty,uuid"`
+	DueDate    *time.Time `json:"due_date" binding:"omitempty"`
+}
+
+// UpdateChecklistItemRequest changes only the fields present in the body. The
+// assignee and due date are cleared by sending null.
+type UpdateChecklistItemRequest struct {
+	Content    string              `json:"content" binding:"omitempty,min=1,max=500"`
+	Completed  *bool               `json:"completed"`
+	AssigneeID Optional[string]    `json:"assignee_id" binding:"omitempty,uuid" swaggertype:"string"`
+	DueDate    Optional[time.Time] `json:"due_date" swaggertype:"string" format:"date-time"`
+	Position   *int                `json:"position" binding:"omitempty,min=1"`
+}
@@ -7,6 +7,7 @@ type Comment struct {
 	CardID    string    `json:"card_id" gorm:"not null"`
 	UserID    string    `json:"user_id" gorm:"not null"`
 	Content   string    `json:"content" gorm:"not null"`
+	Version   int       `json:"version" gorm:"not null;default:1"`
 	CreatedAt time.Time `json:"created_at" gorm:"not null"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"not null"`
 
@@ -0,0 +1,29 @@
+package models
+
+// DeletionSummary reports how many rows and Casbin policies a cascading
+// delete removed.
+type DeletionSummary struct {
+	Organizations        int64 `json:"organizations"`
+	Members              int64 `json:"members"`
+	Projects             int64 `json:"projects"`
+	Boards               int64 `json:"boards"`
+	Lists                int64 `json:"lists"`
+	Cards                int64 `json:"cards"`
+	Comments             int64 `json:"comments"`
+	Attachments          int64 `json:"attachments"`
+	Checklists           int64 `json:"checklists"`
+	ChecklistItems       int64 `json:"checklist_items"`
+	CardLabels           int64 `json:"card_labels"`
+	CardAssignees        int64 `json:"card_assignees"`
+	CardWatchers         int64 `json:"card_watchers"`
+	ListStays            int64 `json:"list_stays"`
+	Labels               int64 `json:"labels"`
+	Activities           int64 `json:"activities"`
+	Webhooks             int64 `json:"webhooks"`
+	Deliveries           int64 `json:"webhook_deliveries"`
+	ListRules            int64 `json:"list_rules"`
+	ListRuleRuns         int64 `json:"list_rule_runs"`
+	Automations          int64 `json:"automations"`
+	AutomationExecutions int64 `json:"automation_executions"`
+	Policies             int64 `json:"policies"`
+}
@@ -2,12 +2,25 @@ package models
 
 import "time"
 
+// Label belongs either to a whole organization, when BoardID is empty, or to
+// a single board of that organization. Names are unique within that scope.
 type Label struct {
-	ID        string    `json:"id" gorm:"primaryKey"`
-	Name      string    `json:"name" gorm:"not null;unique"`
-	Color     string    `json:"color"`
-	CreatedAt time.Time `json:"created_at" gorm:"not null"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"not null"`
+	ID             string    `json:"id" gorm:"primaryKey"`
+	OrganizationID string    `json:"organization_id" gorm:"uniqueIndex:idx_label_scope_name"`
+	BoardID        string    `json:"board_id,omitempty" gorm:"default:'';uniqueIndex:idx_label_scope_name"`
+	Name           string    `json:"name" gorm:"not null;uniqueIndex:idx_label_scope_name"`
+	Color          string    `json:"color"`
+	CreatedAt      time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"not null"`
+	Version        int       `json:"version" gorm:"not null;default:1"`
+}
+
+// ScopeID returns the ID of the resource the label inherits permissions from.
+func (l *Label) ScopeID() string {
+	if l.BoardID != "" {
+		return l.BoardID
+	}
+	return l.OrganizationID
 }
 
 type CreateLabelRequest struct {
@@ -18,4 +31,8 @@ type CreateLabelRequest struct {
 type UpdateLabelRequest struct {
 	Name  *string `json:"name" binding:"omitempty,min=1,max=50"`
 	Color *string `json:"color" binding:"omitempty,hexcolor"`
-}
\ No newline at end of file
+}
+
+type SetCardLabelsRequest struct {
+	LabelIDs []string `json:"label_ids" binding:"required,dive,uuid"`
+}
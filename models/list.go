@@ -1,23 +1,43 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type List struct {
-	ID        string    `json:"id" gorm:"primaryKey"`
-	BoardID   string    `json:"board_id" gorm:"not null"`
-	Name      string    `json:"name" gorm:"not null"`
-	Position  int       `json:"position" gorm:"not null;default:0"`
-	CreatedAt time.Time `json:"created_at" gorm:"not null"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"not null"`
+	ID         string         `json:"id" gorm:"primaryKey"`
+	BoardID    string         `json:"board_id" gorm:"not null;index:idx_list_rank"`
+	Name       string         `json:"name" gorm:"not null"`
+	Rank       string         `json:"rank" gorm:"not null;default:'';index:idx_list_rank"`
+	ArchivedAt *time.Time     `json:"archived_at,omitempty" gorm:"index"`
+	WIPLimit   *int           `json:"wip_limit,omitempty" gorm:"column:wip_limit"`
+	FlowState  string         `json:"flow_state,omitempty" gorm:"not null;default:''"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"not null"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"not null"`
+	Version    int            `json:"version" gorm:"not null;default:1"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	// OverWIPLimit is filled in by board views for lists holding more
+	// unarchived cards than their WIP limit.
+	OverWIPLimit bool `json:"over_wip_limit,omitempty" gorm:"-"`
 
-	Board Board `json:"board" gorm:"foreignKey:BoardID"`
+	Board Board   `json:"-" gorm:"foreignKey:BoardID"`
+	Cards []*Card `json:"cards" gorm:"foreignKey:ListID"`
 }
 
 type CreateListRequest struct {
-	Name string `json:"name" binding:"required,min=1,max=100"`
+	Name      string `json:"name" binding:"required,min=1,max=100"`
+	WIPLimit  *int   `json:"wip_limit" binding:"omitempty,min=1"`
+	FlowState string `json:"flow_state" binding:"omitempty,oneof=not_started started done"`
 }
 
+// UpdateListRequest renames the list, moves it to a 1-based position on its
+// board and/or changes its WIP limit or flow state, which null removes.
 type UpdateListRequest struct {
-	Name     string `json:"name" binding:"omitempty,min=1,max=100"`
-	Position *int   `json:"position" binding:"omitempty"`
+	Name      string           `json:"name" binding:"omitempty,min=1,max=100"`
+	Position  *int             `json:"position" binding:"omitempty"`
+	WIPLimit  Optional[int]    `json:"wip_limit" binding:"omitempty,min=1" swaggertype:"integer"`
+	FlowState Optional[string] `json:"flow_state" binding:"omitempty,oneof=not_started started done" swaggertype:"string"`
 }
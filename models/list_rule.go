@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// List rule types. archive_after archives cards that have been in the list
+// for more than Days days.
+const (
+	ListRuleArchiveAfter = "archive_after"
+)
+
+// ListRule automates housekeeping on a list. Enabled rules are evaluated by
+// a background scheduler, which logs every run.
+type ListRule struct {
+	ID        string     `json:"id" gorm:"primaryKey"`
+	ListID    string     `json:"list_id" gorm:"not null;index"`
+	Type      string     `json:"type" gorm:"not null"`
+	Days      int        `json:"days" gorm:"not null"`
+	Enabled   bool       `json:"enabled" gorm:"not null"`
+	CreatedBy string     `json:"created_by"`
+	LastRunAt *time.Time `json:"last_run_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"not null"`
+	Version   int        `json:"version" gorm:"not null;default:1"`
+}
+
+// ListRuleRun is one evaluation of a rule, with the cards it changed or the
+// error that stopped it.
+type ListRuleRun struct {
+	ID         string    `json:"id" gorm:"primaryKey"`
+	RuleID     string    `json:"rule_id" gorm:"not null;index"`
+	ListID     string    `json:"list_id" gorm:"not null"`
+	StartedAt  time.Time `json:"started_at" gorm:"not null"`
+	FinishedAt time.Time `json:"finished_at" gorm:"not null"`
+	Archived   int       `json:"archived"`
+	CardIDs    []string  `json:"card_ids" gorm:"serializer:json"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type ListRuleRunPage struct {
+	Runs    []ListRuleRun `json:"runs"`
+	Page    int           `json:"page"`
+	PerPage int           `json:"per_page"`
+	Total   int64         `json:"total"`
+}
+
+type CreateListRuleRequest struct {
+	Type    string `json:"type" binding:"required,oneof=archive_after"`
+	Days    int    `json:"days" binding:"required,min=1,max=3650"`
+	Enabled *bool  `json:"enabled"`
+}
+
+type UpdateListRuleRequest struct {
+	Days    *int  `json:"days" binding:"omitempty,min=1,max=3650"`
+	Enabled *bool `json:"enabled"`
+}
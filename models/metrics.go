@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// Flow states of a list, which the board metrics use to tell when work on a
+// card started and finished. When no list on a board has a flow state, its
+// first list counts as not started, its last as done and those in between as
+// started; otherwise lists without one count as not started.
+const (
+	FlowStateNotStarted = "not_started"
+	FlowStateStarted    = "started"
+	FlowStateDone       = "done"
+)
+
+// MetricsQuery sets the window of days, ending today, that flow metrics
+// cover.
+type MetricsQuery struct {
+	Days int `form:"days" binding:"omitempty,min=1,max=365"`
+}
+
+// Normalize fills in the default window of 30 days.
+func (q MetricsQuery) Normalize() MetricsQuery {
+	if q.Days == 0 {
+		q.Days = 30
+	}
+	return q
+}
+
+// BoardMetrics reports on the flow of work across a board. Lead time runs
+// from a card's creation until it entered a done list, and cycle time from
+// when it first entered a started or done list until then; both count the
+// cards finished within the window, in days.
+type BoardMetrics struct {
+	BoardID        string              `json:"board_id"`
+	Since          time.Time           `json:"since"`
+	WIP            []ListWIP           `json:"wip"`
+	CumulativeFlow []CumulativeFlowDay `json:"cumulative_flow"`
+	LeadTime       FlowTimeStats       `json:"lead_time"`
+	CycleTime      FlowTimeStats       `json:"cycle_time"`
+	Throughput     []WeeklyThroughput  `json:"throughput"`
+	AgingWIP       []AgingCard         `json:"aging_wip"`
+}
+
+// ListWIP is how many unarchived cards a list holds against its WIP limit,
+// with the flow state the metrics count it in.
+type ListWIP struct {
+	ListID       string `json:"list_id"`
+	Name         string `json:"name"`
+	FlowState    string `json:"flow_state"`
+	Cards        int64  `json:"cards"`
+	WIPLimit     *int   `json:"wip_limit,omitempty"`
+	OverWIPLimit bool   `json:"over_wip_limit"`
+}
+
+// CumulativeFlowDay is how many cards each list held at the end of a day,
+// keyed by list ID. Archived cards drop out from the day they were archived,
+// except from done lists, where finished work keeps counting.
+type CumulativeFlowDay struct {
+	Date  string         `json:"date"`
+	Lists map[string]int `json:"lists"`
+}
+
+// FlowTimeStats summarizes how many days the cards took, by percentile.
+type FlowTimeStats struct {
+	Cards int     `json:"cards"`
+	P50   float64 `json:"p50"`
+	P85   float64 `json:"p85"`
+	P95   float64 `json:"p95"`
+}
+
+// WeeklyThroughput is how many cards entered a done list in the week
+// starting on Monday WeekStart.
+type WeeklyThroughput struct {
+	WeekStart string `json:"week_start"`
+	Cards     int    `json:"cards"`
+}
+
+// AgingCard is an unarchived card in a started list, with how many days have
+// passed since work on it started.
+type AgingCard struct {
+	CardID    string    `json:"card_id"`
+	Title     string    `json:"title"`
+	ListID    string    `json:"list_id"`
+	StartedAt time.Time `json:"started_at"`
+	AgeDays   float64   `json:"age_days"`
+}
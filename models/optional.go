@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Optional is a request field that tells an absent value apart from an
+// explicit null, so updates can leave a field alone or clear it.
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON is only called when the key is present in the body.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// Ptr returns nil for an explicit null and a pointer to the value otherwise.
+func (o Optional[T]) Ptr() *T {
+	if o.Null {
+		return nil
+	}
+	return &o.Value
+}
+
+// Binding tags on an Optional validate the value inside it. Absent and null
+// fields are nil to the validator, so omitempty skips only those and an
+// explicit empty string is still checked against min.
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterCustomTypeFunc(optionalValue, Optional[string]{}, Optional[int]{}, Optional[time.Time]{})
+	}
+}
+
+func optionalValue(field reflect.Value) any {
+	if !field.FieldByName("Set").Bool() || field.FieldByName("Null").Bool() {
+		return nil
+	}
+	value := field.FieldByName("Value")
+	ptr := reflect.New(value.Type())
+	ptr.Elem().Set(value)
+	return ptr.Interface()
+}
@@ -10,6 +10,7 @@ type Organization struct {
 	OwnerID   string    `json:"owner_id" gorm:"not null"`
 	CreatedAt time.Time `json:"created_at" gorm:"not null"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"not null"`
+	Version   int       `json:"version" gorm:"not null;default:1"`
 }
 
 type CreateOrganizationRequest struct {
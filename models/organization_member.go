@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Organization roles, from most to least privileged. The same names are used
+// as Casbin actions so a higher role implies every role below it.
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+	RoleViewer = "viewer"
+)
+
+const (
+	MemberStatusPending  = "pending"
+	MemberStatusAccepted = "accepted"
+	MemberStatusDeclined = "declined"
+)
+
+type OrganizationMember struct {
+	ID             string    `json:"id" gorm:"primaryKey"`
+	OrganizationID string    `json:"organization_id" gorm:"not null;uniqueIndex:idx_org_member"`
+	UserID         string    `json:"user_id" gorm:"not null;uniqueIndex:idx_org_member"`
+	Role           string    `json:"role" gorm:"not null"`
+	Status         string    `json:"status" gorm:"not null"`
+	InvitedBy      string    `json:"invited_by"`
+	CreatedAt      time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"not null"`
+	Version        int       `json:"version" gorm:"not null;default:1"`
+
+	Organization *Organization `json:"organization,omitempty" gorm:"foreignKey:OrganizationID"`
+	User         *User         `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+type InviteMemberRequest struct {
+	Email    string `json:"email" binding:"omitempty,email"`
+	Username string `json:"username" binding:"required_without=Email"`
+	Role     string `json:"role" binding:"required,oneof=admin member viewer"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner admin member viewer"`
+}
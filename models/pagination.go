@@ -0,0 +1,24 @@
+package models
+
+const DefaultPerPage = 50
+
+// PaginationQuery binds the page and per_page query parameters. Zero values
+// fall back to the first page and DefaultPerPage.
+type PaginationQuery struct {
+	Page    int `form:"page" binding:"omitempty,min=1"`
+	PerPage int `form:"per_page" binding:"omitempty,min=1,max=200"`
+}
+
+func (q PaginationQuery) Normalize() PaginationQuery {
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PerPage == 0 {
+		q.PerPage = DefaultPerPage
+	}
+	return q
+}
+
+func (q PaginationQuery) Offset() int {
+	return (q.Page - 1) * q.PerPage
+}
@@ -0,0 +1,23 @@
+package models
+
+// Actions checked by the Casbin middleware. Roles are granted as policies and
+// map onto these actions through the role hierarchy seeded at startup.
+const (
+	ActionRead   = "read"
+	ActionWrite  = "write"
+	ActionDelete = "delete"
+	ActionAdmin  = "admin"
+	ActionOwn    = "own"
+)
+
+// ResourcePermission is a role granted to a user directly on a resource, on
+// top of whatever the user inherits from the resource's ancestors.
+type ResourcePermission struct {
+	UserID     string `json:"user_id"`
+	ResourceID string `json:"resource_id"`
+	Role       string `json:"role"`
+}
+
+type GrantPermissionRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin member viewer"`
+}
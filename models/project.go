@@ -9,6 +9,7 @@ type Project struct {
 	Description    string    `json:"description"`
 	CreatedAt      time.Time `json:"created_at" gorm:"not null"`
 	UpdatedAt      time.Time `json:"updated_at" gorm:"not null"`
+	Version        int       `json:"version" gorm:"not null;default:1"`
 
 	Organization Organization `json:"-" gorm:"foreignKey:OrganizationID"` //NOTE this acts as a foreignKey in gorm syntax
 }
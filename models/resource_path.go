@@ -0,0 +1,41 @@
+package models
+
+import "strings"
+
+// ResourcePath is the chain of parents of a nested resource, from the
+// organization down to the deepest resource addressed by a request.
+type ResourcePath struct {
+	OrganizationID string `json:"organization_id,omitempty"`
+	ProjectID      string `json:"project_id,omitempty"`
+	BoardID        string `json:"board_id,omitempty"`
+	ListID         string `json:"list_id,omitempty"`
+	CardID         string `json:"card_id,omitempty"`
+	CommentID      string `json:"comment_id,omitempty"`
+	AttachmentID   string `json:"attachment_id,omitempty"`
+	ChecklistID    string `json:"checklist_id,omitempty"`
+	ItemID         string `json:"item_id,omitempty"`
+}
+
+// String renders the path in the same shape as the nested API routes.
+func (p ResourcePath) String() string {
+	segments := []struct{ name, id string }{
+		{"organizations", p.OrganizationID},
+		{"projects", p.ProjectID},
+		{"boards", p.BoardID},
+		{"lists", p.ListID},
+		{"cards", p.CardID},
+		{"comments", p.CommentID},
+		{"attachments", p.AttachmentID},
+		{"checklists", p.ChecklistID},
+		{"items", p.ItemID},
+	}
+
+	var b strings.Builder
+	for _, segment := range segments {
+		if segment.id == "" {
+			continue
+		}
+		b.WriteString("/" + segment.name + "/" + segment.id)
+	}
+	return b.String()
+}
@@ -0,0 +1,8 @@
+package models
+
+// Trash lists the soft-deleted items under a project or board.
+type Trash struct {
+	Boards []Board `json:"boards"`
+	Lists  []List  `json:"lists"`
+	Cards  []Card  `json:"cards"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+)
+
+// Webhook sends the events of a single board, or of every board of an
+// organization when BoardID is empty, to an external URL. Events lists the
+// event types to send, where "card.*" matches every card event; an empty
+// list sends everything.
+type Webhook struct {
+	ID             string    `json:"id" gorm:"primaryKey"`
+	OrganizationID string    `json:"organization_id" gorm:"not null;index"`
+	BoardID        string    `json:"board_id,omitempty" gorm:"default:'';index"`
+	URL            string    `json:"url" gorm:"not null"`
+	Secret         string    `json:"-" gorm:"not null"`
+	Events         []string  `json:"events" gorm:"serializer:json"`
+	Active         bool      `json:"active" gorm:"not null"`
+	CreatedBy      string    `json:"created_by"`
+	CreatedAt      time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"not null"`
+	Version        int       `json:"version" gorm:"not null;default:1"`
+}
+
+// ScopeID returns the ID of the resource the webhook inherits permissions from.
+func (w *Webhook) ScopeID() string {
+	if w.BoardID != "" {
+		return w.BoardID
+	}
+	return w.OrganizationID
+}
+
+// CreatedWebhook is only returned when a webhook is created, the one time its
+// signing secret is shown.
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookDelivery is one event queued for a webhook. Failed attempts are
+// retried with exponential backoff until the delivery succeeds or runs out
+// of attempts.
+type WebhookDelivery struct {
+	ID             string     `json:"id" gorm:"primaryKey"`
+	WebhookID      string     `json:"webhook_id" gorm:"not null;index"`
+	EventID        string     `json:"event_id" gorm:"not null"`
+	EventType      string     `json:"event_type" gorm:"not null"`
+	Payload        string     `json:"-" gorm:"not null"`
+	Status         string     `json:"status" gorm:"not null;index:idx_delivery_due"`
+	Attempts       int        `json:"attempts" gorm:"not null"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"not null;index:idx_delivery_due"`
+	LastStatusCode *int       `json:"last_status_code"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"not null"`
+}
+
+type WebhookDeliveryPage struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	Page       int               `json:"page"`
+	PerPage    int               `json:"per_page"`
+	Total      int64             `json:"total"`
+}
+
+// CreateWebhookRequest registers a webhook. A secret is generated when none
+// is given.
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,startswith=http"`
+	Secret string   `json:"secret" binding:"omitempty,min=16,max=200"`
+	Events []string `json:"events" binding:"omitempty,dive,required,max=100"`
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string   `json:"url" binding:"omitempty,url,startswith=http"`
+	Secret *string   `json:"secret" binding:"omitempty,min=16,max=200"`
+	Events *[]string `json:"events" binding:"omitempty,dive,required,max=100"`
+	Active *bool     `json:"active"`
+}
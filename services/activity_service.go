@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ActivityService struct{}
+
+func NewActivityService() *ActivityService {
+	return &ActivityService{}
+}
+
+// GetCardActivity returns a page of a card's history, newest first.
+func (s *ActivityService) GetCardActivity(cardID string, query models.PaginationQuery) (*models.ActivityPage, error) {
+	return s.getActivity("card_id = ?", cardID, query)
+}
+
+// GetBoardActivity returns a page of the history of every card on a board,
+// including cards that have since been deleted, newest first.
+func (s *ActivityService) GetBoardActivity(boardID string, query models.PaginationQuery) (*models.ActivityPage, error) {
+	return s.getActivity("board_id = ?", boardID, query)
+}
+
+func (s *ActivityService) getActivity(condition, id string, query models.PaginationQuery) (*models.ActivityPage, error) {
+	query = query.Normalize()
+	page := models.ActivityPage{Activities: []models.Activity{}, Page: query.Page, PerPage: query.PerPage}
+
+	if err := database.DB.Model(&models.Activity{}).Where(condition, id).Count(&page.Total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count activity: %w", err)
+	}
+
+	result := database.DB.Preload("Actor").Where(condition, id).
+		Order("created_at DESC, id DESC").
+		Limit(query.PerPage).Offset(query.Offset()).
+		Find(&page.Activities)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve activity: %w", result.Error)
+	}
+	return &page, nil
+}
+
+// recordActivity writes an activity for a card inside the transaction that
+// makes the change, so the history never disagrees with the card. Nothing is
+// recorded when before and after are both given but identical.
+func recordActivity(tx *gorm.DB, actorID string, card *models.Card, action string, before, after map[string]any) error {
+	if before != nil && after != nil {
+		before, after = diffFields(before, after)
+		if len(after) == 0 {
+			return nil
+		}
+	}
+
+	var boardID string
+	if err := tx.Unscoped().Model(&models.List{}).Select("board_id").Where("id = ?", card.ListID).Row().Scan(&boardID); err != nil {
+		return fmt.Errorf("failed to find board of card: %w", err)
+	}
+
+	activity := models.Activity{
+		ID:        uuid.New().String(),
+		BoardID:   boardID,
+		CardID:    card.ID,
+		ActorID:   actorID,
+		Action:    action,
+		Before:    before,
+		After:     after,
+		CreatedAt: time.Now(),
+	}
+	if err := tx.Create(&activity).Error; err != nil {
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+	return nil
+}
+
+// diffFields keeps only the fields whose value differs between two snapshots.
+func diffFields(before, after map[string]any) (map[string]any, map[string]any) {
+	changedBefore := map[string]any{}
+	changedAfter := map[string]any{}
+	for key, value := range after {
+		if !reflect.DeepEqual(before[key], value) {
+			changedBefore[key] = before[key]
+			changedAfter[key] = value
+		}
+	}
+	return changedBefore, changedAfter
+}
+
+// cardFields snapshots the editable fields of a card for activity diffs.
+func cardFields(card *models.Card) map[string]any {
+	var dueDate any
+	if card.DueDate != nil {
+		dueDate = card.DueDate.UTC().Format(time.RFC3339)
+	}
+	return map[string]any{
+		"title":       card.Title,
+		"description": card.Description,
+		"notes":       card.Notes,
+		"due_date":    dueDate,
+	}
+}
+
+func cardPlacement(card *models.Card) map[string]any {
+	return map[string]any{"list_id": card.ListID, "rank": card.Rank}
+}
+
+func labelNames(labels []*models.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func userIDs(users []*models.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
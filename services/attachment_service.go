@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type AttachmentService struct{}
@@ -15,7 +16,7 @@ func NewAttachmentService() *AttachmentService {
 	return &AttachmentService{}
 }
 
-func (s *AttachmentService) CreateAttachment(cardID, fileName, fileURL, fileType string) (*models.Attachment, error) {
+func (s *AttachmentService) CreateAttachment(cardID, fileName, fileURL, fileType, actorID string) (*models.Attachment, error) {
 	attachment := models.Attachment{
 		ID:        uuid.New().String(),
 		CardID:    cardID,
@@ -25,16 +26,56 @@ func (s *AttachmentService) CreateAttachment(cardID, fileName, fileURL, fileType
 		CreatedAt: time.Now(),
 	}
 
-	if err := database.DB.Create(&attachment).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var card models.Card
+		if err := tx.First(&card, "id = ?", cardID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&attachment).Error; err != nil {
+			return err
+		}
+		return recordActivity(tx, actorID, &card, models.ActivityAttachmentAdded, nil, map[string]any{
+			"attachment_id": attachment.ID,
+			"file_name":     fileName,
+			"file_type":     fileType,
+		})
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to create attachment: %w", err)
 	}
 
+	publishCardEvent(cardID, models.EventAttachmentAdded, actorID, attachment)
 	return &attachment, nil
 }
 
-func (s *AttachmentService) DeleteAttachment(attachmentID string) error {
-	if err := database.DB.Delete(&models.Attachment{}, "id = ?", attachmentID).Error; err != nil {
-		return fmt.Errorf("failed to delete attachment: %w", err)
+// DeleteAttachment deletes the attachment if it is still at the given
+// version. An attachment that is already gone is left alone.
+func (s *AttachmentService) DeleteAttachment(attachmentID string, version int, actorID string) error {
+	var attachment models.Attachment
+	var deleted bool
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Limit(1).Find(&attachment, "id = ?", attachmentID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to find attachment: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := claimVersion(tx, &attachment, &attachment.Version, version); err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Attachment{}, "id = ?", attachmentID).Error; err != nil {
+			return fmt.Errorf("failed to delete attachment: %w", err)
+		}
+		deleted = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if deleted {
+		publishCardEvent(attachment.CardID, models.EventAttachmentDeleted, actorID, models.AttachmentDeletedEvent{AttachmentID: attachmentID, CardID: attachment.CardID})
 	}
 	return nil
 }
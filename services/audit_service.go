@@ -0,0 +1,111 @@
+package services
+
+import (
+	"fmt"
+	"kanban-app/api/auth"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+
+	"gorm.io/gorm"
+)
+
+type AuditService struct{}
+
+func NewAuditService() *AuditService {
+	return &AuditService{}
+}
+
+// RecordLogin audits a login attempt. Failed attempts against an existing
+// account are attributed to that account so they show up in the audit log of
+// its organizations.
+func (s *AuditService) RecordLogin(email, ipAddress string, user *models.User, loginErr error) {
+	entry := models.AuditEntry{
+		Action:    models.AuditLoginSucceeded,
+		Details:   map[string]any{"email": email},
+		IPAddress: ipAddress,
+	}
+	if user != nil {
+		entry.ActorID = user.ID
+	}
+	if loginErr != nil {
+		entry.Action = models.AuditLoginFailed
+		entry.Details["reason"] = loginErr.Error()
+		var target models.User
+		if err := database.DB.Select("id").Where("email = ?", email).Limit(1).Find(&target).Error; err == nil {
+			entry.ActorID = target.ID
+		}
+	}
+	auth.NewAuthorizationService().Audit(entry)
+}
+
+func (s *AuditService) RecordRegistration(user *models.User, ipAddress string) {
+	auth.NewAuthorizationService().Audit(models.AuditEntry{
+		ActorID:   user.ID,
+		Action:    models.AuditRegistered,
+		Details:   map[string]any{"username": user.Username, "email": user.Email},
+		IPAddress: ipAddress,
+	})
+}
+
+// GetAuditLog returns a page of an organization's audit log, newest first.
+func (s *AuditService) GetAuditLog(orgID string, query models.AuditQuery) (*models.AuditPage, error) {
+	pagination := query.PaginationQuery.Normalize()
+	page := models.AuditPage{Entries: []models.AuditEntry{}, Page: pagination.Page, PerPage: pagination.PerPage}
+
+	if err := s.auditScope(orgID, query).Count(&page.Total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	result := s.auditScope(orgID, query).
+		Order("created_at DESC, id DESC").
+		Limit(pagination.PerPage).Offset(pagination.Offset()).
+		Find(&page.Entries)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve audit entries: %w", result.Error)
+	}
+	return &page, nil
+}
+
+// ExportAuditLog passes every matching entry to write, oldest first, without
+// loading the whole log into memory. Pagination is ignored.
+func (s *AuditService) ExportAuditLog(orgID string, query models.AuditQuery, write func(*models.AuditEntry) error) error {
+	rows, err := s.auditScope(orgID, query).Order("created_at ASC, id ASC").Rows()
+	if err != nil {
+		return fmt.Errorf("failed to export audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.AuditEntry
+		if err := database.DB.ScanRows(rows, &entry); err != nil {
+			return fmt.Errorf("failed to read audit entry: %w", err)
+		}
+		if err := write(&entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// auditScope selects the organization's own entries plus the account entries
+// of its current members, narrowed by the query's filters.
+func (s *AuditService) auditScope(orgID string, query models.AuditQuery) *gorm.DB {
+	members := database.DB.Model(&models.OrganizationMember{}).Select("user_id").
+		Where("organization_id = ? AND status = ?", orgID, models.MemberStatusAccepted)
+
+	scope := database.DB.Model(&models.AuditEntry{}).
+		Where("organization_id = ? OR (organization_id = '' AND actor_id IN (?))", orgID, members)
+	if query.ActorID != "" {
+		scope = scope.Where("actor_id = ?", query.ActorID)
+	}
+	if query.Action != "" {
+		scope = scope.Where("action = ?", query.Action)
+	}
+	if query.From != nil {
+		scope = scope.Where("created_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		scope = scope.Where("created_at < ?", *query.To)
+	}
+	return scope
+}
@@ -0,0 +1,619 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"log"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxAutomationDepth is how many automations may fire one after another
+	// from a single change before the chain is cut off.
+	maxAutomationDepth = 5
+	// automationExecutionRetention is how long executions are kept.
+	automationExecutionRetention = 30 * 24 * time.Hour
+)
+
+// automationEvent is a change to a card that may fire automations.
+type automationEvent struct {
+	trigger  string
+	cardID   string
+	listID   string   // the list the card moved into
+	labelIDs []string // the labels added to the card
+	// automationID limits the event to one automation, for triggers that
+	// are checked per automation by the scheduler.
+	automationID string
+}
+
+// automationEvents returns the events of a move that took the card into
+// another list.
+func (m *cardMove) automationEvents() []automationEvent {
+	if !m.moved || m.fromListID == m.toListID {
+		return nil
+	}
+	return []automationEvent{{trigger: models.TriggerCardMovedIntoList, cardID: m.cardID, listID: m.toListID}}
+}
+
+// labelsAdded returns the event of labels being added to a card, if any were.
+func labelsAdded(cardID string, labelIDs []string) []automationEvent {
+	if len(labelIDs) == 0 {
+		return nil
+	}
+	return []automationEvent{{trigger: models.TriggerLabelAdded, cardID: cardID, labelIDs: labelIDs}}
+}
+
+// afterChange fires the automations for the events of a change to the card
+// once the change has been committed and announced, and returns the card as
+// they left it.
+func (s *CardService) afterChange(card *models.Card, events ...automationEvent) (*models.Card, error) {
+	if !s.automationService.dispatch(events...) {
+		return card, nil
+	}
+	return s.GetCardByID(card.ID)
+}
+
+type AutomationService struct {
+	cardService    *CardService
+	commentService *CommentService
+}
+
+func NewAutomationService() *AutomationService {
+	return NewCardService().automationService
+}
+
+func newAutomationService(cardService *CardService) *AutomationService {
+	return &AutomationService{cardService: cardService, commentService: NewCommentService()}
+}
+
+func (s *AutomationService) CreateAutomation(boardID, actorID string, req models.CreateAutomationRequest) (*models.Automation, error) {
+	automation := models.Automation{
+		ID:         uuid.New().String(),
+		BoardID:    boardID,
+		Name:       req.Name,
+		Trigger:    req.Trigger,
+		Conditions: req.Conditions,
+		Actions:    req.Actions,
+		Enabled:    req.Enabled == nil || *req.Enabled,
+		CreatedBy:  actorID,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if err := s.validate(&automation); err != nil {
+		return nil, err
+	}
+	// Due dates that passed before the automation existed don't fire it
+	automation.DueCheckedAt = &automation.CreatedAt
+
+	if err := database.DB.Create(&automation).Error; err != nil {
+		return nil, fmt.Errorf("failed to create automation: %w", err)
+	}
+
+	log.Printf("Automation created: %s on board %s by user %s\n", automation.ID, boardID, actorID)
+	return &automation, nil
+}
+
+func (s *AutomationService) GetAutomations(boardID string) ([]models.Automation, error) {
+	var automations []models.Automation
+	result := database.DB.Where("board_id = ?", boardID).Order("created_at ASC").Find(&automations)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve automations: %w", result.Error)
+	}
+	return automations, nil
+}
+
+func (s *AutomationService) GetAutomationByID(boardID, automationID string) (*models.Automation, error) {
+	var automation models.Automation
+	result := database.DB.First(&automation, "id = ? AND board_id = ?", automationID, boardID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("automation not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve automation: %w", result.Error)
+	}
+	return &automation, nil
+}
+
+func (s *AutomationService) UpdateAutomation(boardID, automationID string, version int, updateReq models.UpdateAutomationRequest) (*models.Automation, error) {
+	automation, err := s.GetAutomationByID(boardID, automationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The changed fields are written from the struct, as only that applies
+	// their JSON serializers
+	fields := []string{}
+	if updateReq.Name != nil {
+		automation.Name = *updateReq.Name
+		fields = append(fields, "Name")
+	}
+	if updateReq.Trigger != nil {
+		automation.Trigger = *updateReq.Trigger
+		fields = append(fields, "Trigger")
+	}
+	if updateReq.Conditions != nil {
+		automation.Conditions = *updateReq.Conditions
+		fields = append(fields, "Conditions")
+	}
+	if updateReq.Actions != nil {
+		automation.Actions = *updateReq.Actions
+		fields = append(fields, "Actions")
+	}
+	if updateReq.Enabled != nil {
+		automation.Enabled = *updateReq.Enabled
+		fields = append(fields, "Enabled")
+	}
+	if err := s.validate(automation); err != nil {
+		return nil, err
+	}
+	automation.UpdatedAt = time.Now()
+	fields = append(fields, "UpdatedAt")
+	if updateReq.Trigger != nil || updateReq.Enabled != nil {
+		automation.DueCheckedAt = &automation.UpdatedAt
+		fields = append(fields, "DueCheckedAt")
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, automation, &automation.Version, version); err != nil {
+			return err
+		}
+		return tx.Model(automation).Select(fields).UpdateColumns(automation).Error
+	})
+	if err != nil {
+		if errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update automation: %w", err)
+	}
+	return automation, nil
+}
+
+// DeleteAutomation removes the automation together with its executions.
+func (s *AutomationService) DeleteAutomation(boardID, automationID string, version int) error {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var automation models.Automation
+		if err := tx.First(&automation, "id = ? AND board_id = ?", automationID, boardID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("automation not found or already deleted")
+			}
+			return err
+		}
+		if err := claimVersion(tx, &automation, &automation.Version, version); err != nil {
+			return err
+		}
+		return newCascadeDeleter(tx).deleteAutomations([]string{automationID})
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Automation deleted: ID %s\n", automationID)
+	return nil
+}
+
+// GetExecutions returns a page of an automation's executions, newest first.
+func (s *AutomationService) GetExecutions(boardID, automationID string, query models.PaginationQuery) (*models.AutomationExecutionPage, error) {
+	if _, err := s.GetAutomationByID(boardID, automationID); err != nil {
+		return nil, err
+	}
+
+	query = query.Normalize()
+	page := models.AutomationExecutionPage{Executions: []models.AutomationExecution{}, Page: query.Page, PerPage: query.PerPage}
+
+	if err := database.DB.Model(&models.AutomationExecution{}).Where("automation_id = ?", automationID).Count(&page.Total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count executions: %w", err)
+	}
+
+	result := database.DB.Where("automation_id = ?", automationID).
+		Order("created_at DESC, id DESC").
+		Limit(query.PerPage).Offset(query.Offset()).
+		Find(&page.Executions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve executions: %w", result.Error)
+	}
+	return &page, nil
+}
+
+// validate checks that the lists and labels the automation refers to are on
+// its board and that it has what its trigger and actions need.
+func (s *AutomationService) validate(automation *models.Automation) error {
+	path, err := s.cardService.hierarchyService.ResolvePath(models.ResourcePath{BoardID: automation.BoardID})
+	if err != nil {
+		return err
+	}
+	checkList := func(listID string) error {
+		var count int64
+		if err := database.DB.Model(&models.List{}).Where("id = ? AND board_id = ?", listID, automation.BoardID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check list: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("invalid automation: list %s is not on this board", listID)
+		}
+		return nil
+	}
+	checkLabel := func(labelID string) error {
+		label, err := s.cardService.labelService.GetLabelByID(labelID)
+		if err != nil {
+			return fmt.Errorf("invalid automation: %w", err)
+		}
+		if err := s.cardService.labelService.checkLabelScope(label, path.OrganizationID, automation.BoardID); err != nil {
+			return fmt.Errorf("invalid automation: %w", err)
+		}
+		return nil
+	}
+
+	switch automation.Trigger.Type {
+	case models.TriggerCardMovedIntoList:
+		if automation.Trigger.ListID == "" {
+			return errors.New("invalid automation: a card_moved_into_list trigger needs a list_id")
+		}
+		if err := checkList(automation.Trigger.ListID); err != nil {
+			return err
+		}
+	case models.TriggerLabelAdded:
+		if automation.Trigger.LabelID == "" {
+			return errors.New("invalid automation: a label_added trigger needs a label_id")
+		}
+		if err := checkLabel(automation.Trigger.LabelID); err != nil {
+			return err
+		}
+	}
+
+	for _, labelID := range automation.Conditions.LabelIDs {
+		if err := checkLabel(labelID); err != nil {
+			return err
+		}
+	}
+	for _, userID := range automation.Conditions.AssigneeIDs {
+		if _, err := s.cardService.memberService.GetAcceptedMember(path.OrganizationID, userID); err != nil {
+			return fmt.Errorf("invalid automation: %w", err)
+		}
+	}
+
+	for _, action := range automation.Actions {
+		switch action.Type {
+		case models.AutomationMoveToList:
+			if action.ListID == "" {
+				return errors.New("invalid automation: a move_to_list action needs a list_id")
+			}
+			if err := checkList(action.ListID); err != nil {
+				return err
+			}
+		case models.AutomationAddLabel:
+			if action.LabelID == "" {
+				return errors.New("invalid automation: an add_label action needs a label_id")
+			}
+			if err := checkLabel(action.LabelID); err != nil {
+				return err
+			}
+		case models.AutomationAddComment:
+			if action.Text == "" {
+				return errors.New("invalid automation: an add_comment action needs a text")
+			}
+		}
+	}
+	return nil
+}
+
+// dispatch fires the automations matching the events, then those their
+// actions fire in turn, and reports whether any ran. An automation runs at
+// most once per card in a chain, and a chain stops after maxAutomationDepth
+// automations. Failures are recorded in the executions and logged rather
+// than returned, since the change that fired the automations has already
+// been made.
+func (s *AutomationService) dispatch(events ...automationEvent) bool {
+	ran := false
+	fired := map[string]bool{}
+	for depth := 0; len(events) > 0; depth++ {
+		var next []automationEvent
+		for _, event := range events {
+			followUps, executed := s.fire(event, depth, fired)
+			next = append(next, followUps...)
+			ran = ran || executed
+		}
+		events = next
+	}
+	return ran
+}
+
+// fire runs the automations of the card's board that the event triggers and
+// whose conditions the card meets, and returns the events their actions
+// caused.
+func (s *AutomationService) fire(event automationEvent, depth int, fired map[string]bool) ([]automationEvent, bool) {
+	path, err := s.cardService.hierarchyService.ResolvePath(models.ResourcePath{CardID: event.cardID})
+	if err != nil {
+		return nil, false
+	}
+
+	var automations []models.Automation
+	if err := database.DB.Where("board_id = ? AND enabled = ?", path.BoardID, true).Order("created_at ASC").Find(&automations).Error; err != nil {
+		log.Printf("Failed to find automations of board %s: %v\n", path.BoardID, err)
+		return nil, false
+	}
+
+	var followUps []automationEvent
+	executed := false
+	for i := range automations {
+		automation := &automations[i]
+		if !triggers(automation, event) {
+			continue
+		}
+		card, err := s.loadCard(database.DB, event.cardID)
+		if err != nil || card.ArchivedAt != nil || !conditionsHold(automation.Conditions, card) {
+			continue
+		}
+
+		execution := models.AutomationExecution{
+			ID:           uuid.New().String(),
+			AutomationID: automation.ID,
+			CardID:       event.cardID,
+			Trigger:      event.trigger,
+			Depth:        depth,
+			Actions:      []string{},
+			CreatedAt:    time.Now(),
+		}
+		key := automation.ID + "/" + event.cardID
+		switch {
+		case depth >= maxAutomationDepth:
+			execution.Status = models.ExecutionSkipped
+			execution.Error = fmt.Sprintf("loop guard: more than %d automations fired in a row", maxAutomationDepth)
+		case fired[key]:
+			execution.Status = models.ExecutionSkipped
+			execution.Error = "loop guard: the automation already ran on this card in this chain"
+		default:
+			fired[key] = true
+			events, err := s.execute(automation, event.cardID, &execution)
+			if err != nil {
+				execution.Status = models.ExecutionFailed
+				execution.Error = err.Error()
+			} else {
+				execution.Status = models.ExecutionSucceeded
+				followUps = append(followUps, events...)
+				executed = true
+			}
+		}
+
+		if err := database.DB.Create(&execution).Error; err != nil {
+			log.Printf("Failed to record execution of automation %s: %v\n", automation.ID, err)
+		}
+		if execution.Status != models.ExecutionSucceeded {
+			log.Printf("Automation %s on card %s %s: %s\n", automation.ID, event.cardID, execution.Status, execution.Error)
+		}
+	}
+	return followUps, executed
+}
+
+// triggers reports whether the event fires the automation.
+func triggers(automation *models.Automation, event automationEvent) bool {
+	if automation.Trigger.Type != event.trigger || (event.automationID != "" && event.automationID != automation.ID) {
+		return false
+	}
+	switch event.trigger {
+	case models.TriggerCardMovedIntoList:
+		return automation.Trigger.ListID == event.listID
+	case models.TriggerLabelAdded:
+		return slices.Contains(event.labelIDs, automation.Trigger.LabelID)
+	}
+	return true
+}
+
+// conditionsHold reports whether the card carries every label and is
+// assigned to every user the conditions name.
+func conditionsHold(conditions models.AutomationConditions, card *models.Card) bool {
+	for _, labelID := range conditions.LabelIDs {
+		if !slices.ContainsFunc(card.Labels, func(l *models.Label) bool { return l.ID == labelID }) {
+			return false
+		}
+	}
+	for _, userID := range conditions.AssigneeIDs {
+		if !slices.ContainsFunc(card.Assignees, func(u *models.User) bool { return u.ID == userID }) {
+			return false
+		}
+	}
+	return true
+}
+
+// execute applies the automation's actions to the card in one transaction,
+// on behalf of the user who created the automation, and announces them once
+// it has committed.
+func (s *AutomationService) execute(automation *models.Automation, cardID string, execution *models.AutomationExecution) ([]automationEvent, error) {
+	details := map[string]any{"automation_id": automation.ID}
+	checks := []permissionCheck{{cardID, models.ActionWrite, "run automations on this card", details}}
+	for _, action := range automation.Actions {
+		if action.Type == models.AutomationMoveToList {
+			checks = append(checks, permissionCheck{action.ListID, models.ActionWrite, "move cards into the destination list", details})
+		}
+	}
+	if err := authorizeAll(automation.CreatedBy, checks...); err != nil {
+		return nil, err
+	}
+
+	var announcements []func() error
+	var followUps []automationEvent
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		card, err := s.loadCard(tx, cardID)
+		if err != nil {
+			return err
+		}
+		if err := claimVersion(tx, card, &card.Version, AnyVersion); err != nil {
+			return err
+		}
+		for _, action := range automation.Actions {
+			announce, events, err := s.apply(tx, automation.CreatedBy, card, action)
+			if err != nil {
+				return fmt.Errorf("%s: %w", action.Type, err)
+			}
+			announcements = append(announcements, announce)
+			followUps = append(followUps, events...)
+			execution.Actions = append(execution.Actions, action.Type)
+		}
+		return nil
+	})
+	if err != nil {
+		execution.Actions = []string{}
+		return nil, err
+	}
+
+	for _, announce := range announcements {
+		if err := announce(); err != nil {
+			log.Printf("Failed to announce automation %s on card %s: %v\n", automation.ID, cardID, err)
+		}
+	}
+	return followUps, nil
+}
+
+// apply makes one action's change to the card inside tx and returns how to
+// announce it and the events it causes.
+func (s *AutomationService) apply(tx *gorm.DB, actorID string, card *models.Card, action models.AutomationAction) (func() error, []automationEvent, error) {
+	publish := func(eventType string) func() error {
+		return func() error {
+			_, err := s.cardService.reloadAndPublish(card.ID, eventType, actorID)
+			return err
+		}
+	}
+
+	switch action.Type {
+	case models.AutomationMoveToList:
+		position := math.MaxInt
+		if action.Position != nil {
+			position = *action.Position
+		}
+		move, err := s.cardService.checkMove(tx, card, action.ListID, actorID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := s.cardService.applyMove(tx, card, move, position, actorID); err != nil {
+			return nil, nil, err
+		}
+		return func() error { return s.cardService.announceMove(move, actorID) }, move.automationEvents(), nil
+
+	case models.AutomationAddLabel:
+		labelIDs := make([]string, 0, len(card.Labels)+1)
+		for _, label := range card.Labels {
+			labelIDs = append(labelIDs, label.ID)
+		}
+		if slices.Contains(labelIDs, action.LabelID) {
+			return func() error { return nil }, nil, nil
+		}
+		added, err := s.cardService.setLabels(tx, card, append(labelIDs, action.LabelID), actorID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return publish(models.EventCardLabelsChanged), labelsAdded(card.ID, added), nil
+
+	case models.AutomationSetDueDate:
+		var dueDate *time.Time
+		if action.DueInDays != nil {
+			due := time.Now().AddDate(0, 0, *action.DueInDays)
+			dueDate = &due
+		}
+		if err := s.cardService.setDueDate(tx, card, dueDate, actorID); err != nil {
+			return nil, nil, err
+		}
+		return publish(models.EventCardUpdated), nil, nil
+
+	case models.AutomationAddComment:
+		comment := models.Comment{
+			ID:        uuid.New().String(),
+			CardID:    card.ID,
+			UserID:    actorID,
+			Content:   action.Text,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		if err := s.commentService.addComment(tx, card, &comment); err != nil {
+			return nil, nil, fmt.Errorf("failed to add comment: %w", err)
+		}
+		return func() error {
+			publishCardEvent(card.ID, models.EventCommentAdded, actorID, comment)
+			return nil
+		}, nil, nil
+	}
+	return nil, nil, fmt.Errorf("unknown action %s", action.Type)
+}
+
+func (s *AutomationService) loadCard(db *gorm.DB, cardID string) (*models.Card, error) {
+	var card models.Card
+	if err := db.Preload("Labels").Preload("Assignees").First(&card, "id = ?", cardID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("card not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve card: %w", err)
+	}
+	return &card, nil
+}
+
+// RunDueDates fires every enabled due_date_passed automation for the cards
+// of its board whose due date has passed since it last checked, leaving out
+// archived cards and lists, and returns how many cards it fired for.
+func (s *AutomationService) RunDueDates() (int, error) {
+	var automations []models.Automation
+	result := database.DB.Joins("JOIN boards ON boards.id = automations.board_id AND boards.deleted_at IS NULL").
+		Where("automations.enabled = ?", true).
+		Order("automations.created_at ASC").
+		Find(&automations)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to find automations: %w", result.Error)
+	}
+
+	fired := 0
+	for i := range automations {
+		automation := &automations[i]
+		if automation.Trigger.Type != models.TriggerDueDatePassed {
+			continue
+		}
+
+		now := time.Now()
+		since := automation.CreatedAt
+		if automation.DueCheckedAt != nil {
+			since = *automation.DueCheckedAt
+		}
+		var cardIDs []string
+		result := database.DB.Model(&models.Card{}).
+			Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL AND lists.archived_at IS NULL").
+			Where("lists.board_id = ? AND cards.archived_at IS NULL AND cards.due_date > ? AND cards.due_date <= ?", automation.BoardID, since, now).
+			Order("cards.due_date ASC").
+			Pluck("cards.id", &cardIDs)
+		if result.Error != nil {
+			return fired, fmt.Errorf("failed to find cards past their due date: %w", result.Error)
+		}
+		if err := database.DB.Model(automation).UpdateColumn("due_checked_at", now).Error; err != nil {
+			return fired, fmt.Errorf("failed to update automation: %w", err)
+		}
+
+		for _, cardID := range cardIDs {
+			s.dispatch(automationEvent{trigger: models.TriggerDueDatePassed, cardID: cardID, automationID: automation.ID})
+			fired++
+		}
+	}
+
+	cutoff := time.Now().Add(-automationExecutionRetention)
+	if err := database.DB.Where("created_at < ?", cutoff).Delete(&models.AutomationExecution{}).Error; err != nil {
+		return fired, fmt.Errorf("failed to prune automation executions: %w", err)
+	}
+	return fired, nil
+}
+
+// StartScheduler runs RunDueDates in the background every interval.
+func (s *AutomationService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			fired, err := s.RunDueDates()
+			if err != nil {
+				log.Printf("Due date automations failed: %v\n", err)
+			} else if fired > 0 {
+				log.Printf("Due date automations fired for %d cards\n", fired)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// execution is the outcome of an automation on the card in a chain.
+type execution struct {
+	automation int
+	depth      int
+	status     string
+	err        string
+}
+
+func TestAutomationLoopGuard(t *testing.T) {
+	tests := []struct {
+		name  string
+		lists int
+		// moves holds, for each automation, the list that fires it and the
+		// list it moves the card into, by index
+		moves [][2]int
+		// enter is the list the card is moved into to start the chain
+		enter          int
+		wantList       int
+		wantExecutions []execution
+	}{
+		{
+			name:     "two automations moving a card back and forth",
+			lists:    3,
+			moves:    [][2]int{{1, 2}, {2, 1}},
+			enter:    1,
+			wantList: 1,
+			wantExecutions: []execution{
+				{0, 0, models.ExecutionSucceeded, ""},
+				{1, 1, models.ExecutionSucceeded, ""},
+				{0, 2, models.ExecutionSkipped, "already ran on this card"},
+			},
+		},
+		{
+			name:     "automation moving a card into the list that fired it",
+			lists:    3,
+			moves:    [][2]int{{1, 2}, {2, 2}},
+			enter:    1,
+			wantList: 2,
+			wantExecutions: []execution{
+				{0, 0, models.ExecutionSucceeded, ""},
+				{1, 1, models.ExecutionSucceeded, ""},
+			},
+		},
+		{
+			name:     "chain longer than the depth allows",
+			lists:    8,
+			moves:    [][2]int{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 6}, {6, 7}},
+			enter:    1,
+			wantList: 6,
+			wantExecutions: []execution{
+				{0, 0, models.ExecutionSucceeded, ""},
+				{1, 1, models.ExecutionSucceeded, ""},
+				{2, 2, models.ExecutionSucceeded, ""},
+				{3, 3, models.ExecutionSucceeded, ""},
+				{4, 4, models.ExecutionSucceeded, ""},
+				{5, maxAutomationDepth, models.ExecutionSkipped, "more than 5 automations"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBoard(t)
+			lists := make([]string, tt.lists)
+			for i := range lists {
+				lists[i] = addTestList(t, b, "List "+string(rune('A'+i)))
+			}
+			card := addTestCard(t, b, lists[0], "Card")
+
+			automationService := NewAutomationService()
+			automations := make(map[string]int, len(tt.moves))
+			for i, move := range tt.moves {
+				automation, err := automationService.CreateAutomation(b.BoardID, b.OwnerID, models.CreateAutomationRequest{
+					Name:    "Automation " + string(rune('A'+i)),
+					Trigger: models.AutomationTrigger{Type: models.TriggerCardMovedIntoList, ListID: lists[move[0]]},
+					Actions: []models.AutomationAction{{Type: models.AutomationMoveToList, ListID: lists[move[1]]}},
+				})
+				if err != nil {
+					t.Fatalf("CreateAutomation: %v", err)
+				}
+				automations[automation.ID] = i
+			}
+
+			_, err := NewCardService().MoveCardToList(card, AnyVersion, b.OwnerID, models.MoveCardRequest{ListID: lists[tt.enter]})
+			if err != nil {
+				t.Fatalf("MoveCardToList: %v", err)
+			}
+
+			if got := loadTestCard(t, card).ListID; got != lists[tt.wantList] {
+				t.Errorf("card ended in list %d, want %d", slices.Index(lists, got), tt.wantList)
+			}
+
+			var executions []models.AutomationExecution
+			if err := database.DB.Where("card_id = ?", card).Order("depth ASC").Find(&executions).Error; err != nil {
+				t.Fatal(err)
+			}
+			if len(executions) != len(tt.wantExecutions) {
+				t.Fatalf("got %d executions, want %d", len(executions), len(tt.wantExecutions))
+			}
+			for i, want := range tt.wantExecutions {
+				got := executions[i]
+				if automations[got.AutomationID] != want.automation || got.Depth != want.depth || got.Status != want.status {
+					t.Errorf("execution %d: automation %d at depth %d %s, want automation %d at depth %d %s",
+						i, automations[got.AutomationID], got.Depth, got.Status, want.automation, want.depth, want.status)
+				}
+				if !strings.Contains(got.Error, want.err) {
+					t.Errorf("execution %d error = %q, want it to contain %q", i, got.Error, want.err)
+				}
+			}
+		})
+	}
+}
@@ -14,10 +14,14 @@ import (
 	"gorm.io/gorm"
 )
 
-type BoardService struct{}
+type BoardService struct {
+	checklistService *ChecklistService
+}
 
 func NewBoardService() *BoardService {
-	return &BoardService{}
+	return &BoardService{
+		checklistService: NewChecklistService(),
+	}
 }
 
 func (s *BoardService) CreateBoard(projectID, name, description, userID string) (*models.Board, error) {
@@ -26,6 +30,7 @@ func (s *BoardService) CreateBoard(projectID, name, description, userID string)
 		ProjectID:   projectID,
 		Name:        name,
 		Description: description,
+		WIPPolicy:   models.WIPPolicyStrict,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -38,12 +43,12 @@ func (s *BoardService) CreateBoard(projectID, name, description, userID string)
 		return nil, fmt.Errorf("failed to create board: %w", result.Error)
 	}
 
-	log.Printf("Board created: %s in project %s\n", board.Name, board.ProjectID)
+	log.Printf("Board created: %s in project %s by user %s\n", board.Name, board.ProjectID, userID)
 
-	// Add policy to Casbin
-	_, err := auth.NewAuthorizationService().AddPolicy(userID, board.ID, "owner")
+	// Link the board to its project so project grants apply to it
+	_, err := auth.NewAuthorizationService().AddResourceParent(board.ID, projectID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add policy for new board: %w", err)
+		return nil, fmt.Errorf("failed to link new board to project: %w", err)
 	}
 
 	return &board, nil
@@ -70,56 +75,115 @@ func (s *BoardService) GetBoardByID(boardID string) (*models.Board, error) {
 	return &board, nil
 }
 
-func (s *BoardService) UpdateBoard(boardID string, updateReq models.UpdateBoardRequest) (*models.Board, error) {
+func (s *BoardService) UpdateBoard(boardID string, version int, updateReq models.UpdateBoardRequest) (*models.Board, error) {
 	board, err := s.GetBoardByID(boardID)
 	if err != nil {
 		return nil, err
 	}
 
+	columns := map[string]any{}
 	if updateReq.Name != "" {
 		board.Name = updateReq.Name
+		columns["name"] = board.Name
 	}
 	if updateReq.Description != "" {
 		board.Description = updateReq.Description
+		columns["description"] = board.Description
+	}
+	if updateReq.WIPPolicy != "" {
+		board.WIPPolicy = updateReq.WIPPolicy
+		columns["wip_policy"] = board.WIPPolicy
 	}
 	board.UpdatedAt = time.Now()
+	columns["updated_at"] = board.UpdatedAt
 
-	result := database.DB.Save(&board)
-	if result.Error != nil {
-		if strings.Contains(result.Error.Error(), "UNIQUE constraint failed") && strings.Contains(result.Error.Error(), "boards.name") {
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, board, &board.Version, version); err != nil {
+			return err
+		}
+		return tx.Model(board).UpdateColumns(columns).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") && strings.Contains(err.Error(), "boards.name") {
 			return nil, errors.New("board name already exists within this project")
 		}
-		return nil, fmt.Errorf("failed to update board: %w", result.Error)
+		if errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update board: %w", err)
 	}
 	log.Printf("Board updated: %s (ID: %s)\n", board.Name, board.ID)
 	return board, nil
 }
 
-func (s *BoardService) DeleteBoard(boardID string) error {
-	result := database.DB.Delete(&models.Board{}, "id = ?", boardID)
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete board: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return errors.New("board not found or already deleted")
+// DeleteBoard moves the board to the trash. Its lists and cards stay attached
+// and come back with it when restored, until the trash is purged.
+func (s *BoardService) DeleteBoard(boardID string, version int) (*models.DeletionSummary, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var board models.Board
+		if err := tx.First(&board, "id = ?", boardID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("board not found or already deleted")
+			}
+			return err
+		}
+		if err := claimVersion(tx, &board, &board.Version, version); err != nil {
+			return err
+		}
+		return tx.Delete(&board).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "not found or already deleted") || errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to delete board: %w", err)
 	}
-	log.Printf("Board deleted: ID %s\n", boardID)
-	return nil
+	log.Printf("Board moved to trash: ID %s\n", boardID)
+	return &models.DeletionSummary{Boards: 1}, nil
 }
 
-func (s *BoardService) GetBoardDetails(boardID string) (*models.Board, error) {
+// GetBoardDetails returns a snapshot of the board with its lists and cards in
+// rank order. Cards carry their labels, assignees, watchers, attachments,
+// checklist progress and a comment count; the comments themselves are fetched
+// per card. Lists holding more cards than their WIP limit are flagged.
+// Archived lists and cards are left out unless includeArchived is set.
+func (s *BoardService) GetBoardDetails(boardID string, includeArchived bool) (*models.Board, error) {
+	unarchived := func(db *gorm.DB, table string) *gorm.DB {
+		if includeArchived {
+			return db
+		}
+		return db.Where(table + ".archived_at IS NULL")
+	}
+
 	var board models.Board
 	result := database.DB.Preload("Lists", func(db *gorm.DB) *gorm.DB {
-		return db.Order("lists.position ASC")
+		return unarchived(db, "lists").Order("lists.rank ASC, lists.id ASC")
 	}).Preload("Lists.Cards", func(db *gorm.DB) *gorm.DB {
-		return db.Order("cards.position ASC")
-	}).Preload("Lists.Cards.Labels").Preload("Lists.Cards.Comments").Preload("Lists.Cards.Comments.User").Preload("Lists.Cards.Attachments").First(&board, "id = ?", boardID)
+		return unarchived(db, "cards").Select("cards.*, (SELECT COUNT(*) FROM comments WHERE comments.card_id = cards.id) AS comment_count").Order("cards.rank ASC, cards.id ASC")
+	}).Preload("Lists.Cards.Labels").Preload("Lists.Cards.Assignees").Preload("Lists.Cards.Watchers").Preload("Lists.Cards.Attachments").First(&board, "id = ?", boardID)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, errors.New("board not found")
 		}
 		return nil, fmt.Errorf("failed to retrieve board details: %w", result.Error)
 	}
+
+	// Empty boards and lists still render as empty arrays in the snapshot.
+	if board.Lists == nil {
+		board.Lists = []*models.List{}
+	}
+	var cards []*models.Card
+	for _, list := range board.Lists {
+		if list.Cards == nil {
+			list.Cards = []*models.Card{}
+		}
+		cards = append(cards, list.Cards...)
+	}
+	markOverWIPLimit(board.Lists)
+
+	if err := s.checklistService.AttachProgress(cards); err != nil {
+		return nil, err
+	}
 	return &board, nil
 }
 
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"errors"
+	"kanban-app/api/models"
+	"slices"
+	"testing"
+)
+
+func TestArchivedListRefusesReturningCards(t *testing.T) {
+	b := newTestBoard(t)
+	listID := addTestList(t, b, "Done")
+	archived := addTestCard(t, b, listID, "Archived")
+	trashed := addTestCard(t, b, listID, "Trashed")
+
+	cards := NewCardService()
+	if _, err := cards.ArchiveCard(archived, AnyVersion, b.OwnerID); err != nil {
+		t.Fatalf("ArchiveCard: %v", err)
+	}
+	if _, err := cards.DeleteCard(trashed, AnyVersion, b.OwnerID); err != nil {
+		t.Fatalf("DeleteCard: %v", err)
+	}
+	if _, err := NewListService().ArchiveList(listID, AnyVersion, b.OwnerID); err != nil {
+		t.Fatalf("ArchiveList: %v", err)
+	}
+
+	if _, err := cards.UnarchiveCard(archived, AnyVersion, b.OwnerID); !errors.Is(err, errListArchived) {
+		t.Errorf("UnarchiveCard error = %v, want %v", err, errListArchived)
+	}
+	if card := loadTestCard(t, archived); card.ArchivedAt == nil {
+		t.Error("refused card was unarchived")
+	}
+	if _, err := NewTrashService().RestoreCard(trashed, b.OwnerID); !errors.Is(err, errListArchived) {
+		t.Errorf("RestoreCard error = %v, want %v", err, errListArchived)
+	}
+	if card := loadTestCard(t, trashed); !card.DeletedAt.Valid {
+		t.Error("refused card was restored")
+	}
+}
+
+func TestArchivedCardsLeftOutOfListings(t *testing.T) {
+	b := newTestBoard(t)
+	openList := addTestList(t, b, "Open")
+	archivedList := addTestList(t, b, "Shelved")
+	open := addTestCard(t, b, openList, "Open")
+	archived := addTestCard(t, b, openList, "Archived")
+	shelved := addTestCard(t, b, archivedList, "Shelved")
+
+	label, err := NewLabelService().CreateLabel(b.OrgID, b.BoardID, "Bug", "#ff0000", b.OwnerID)
+	if err != nil {
+		t.Fatalf("CreateLabel: %v", err)
+	}
+	cards := NewCardService()
+	for _, id := range []string{open, archived, shelved} {
+		if _, err := cards.AddLabelToCard(id, AnyVersion, label.ID, b.OwnerID); err != nil {
+			t.Fatalf("AddLabelToCard: %v", err)
+		}
+		if _, err := cards.AddAssignee(id, AnyVersion, b.OwnerID, b.OwnerID); err != nil {
+			t.Fatalf("AddAssignee: %v", err)
+		}
+	}
+	if _, err := cards.ArchiveCard(archived, AnyVersion, b.OwnerID); err != nil {
+		t.Fatalf("ArchiveCard: %v", err)
+	}
+	if _, err := NewListService().ArchiveList(archivedList, AnyVersion, b.OwnerID); err != nil {
+		t.Fatalf("ArchiveList: %v", err)
+	}
+
+	listings := []struct {
+		name string
+		get  func(includeArchived bool) ([]models.Card, error)
+	}{
+		{"by label", func(includeArchived bool) ([]models.Card, error) {
+			return cards.GetCardsByLabel(b.OrgID, b.BoardID, label.ID, includeArchived)
+		}},
+		{"assigned", func(includeArchived bool) ([]models.Card, error) {
+			return cards.GetAssignedCards(b.OwnerID, includeArchived)
+		}},
+	}
+	for _, listing := range listings {
+		for _, tc := range []struct {
+			includeArchived bool
+			want            []string
+		}{
+			{false, []string{open}},
+			{true, []string{open, archived, shelved}},
+		} {
+			got, err := listing.get(tc.includeArchived)
+			if err != nil {
+				t.Fatalf("%s: %v", listing.name, err)
+			}
+			ids := make([]string, len(got))
+			for i, card := range got {
+				ids[i] = card.ID
+			}
+			slices.Sort(ids)
+			want := slices.Sorted(slices.Values(tc.want))
+			if !slices.Equal(ids, want) {
+				t.Errorf("%s with include_archived=%v = %v, want %v", listing.name, tc.includeArchived, ids, want)
+			}
+		}
+	}
+}
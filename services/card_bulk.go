@@ -0,0 +1,264 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"log"
+	"math"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BulkUpdate applies a batch of card operations on a board in one
+// transaction: either every operation is applied or, as soon as one fails,
+// none are. Each operation is authorized on its own card first, as if it had
+// been sent alone, against the board as it was before the batch.
+func (s *CardService) BulkUpdate(boardID, actorID string, req models.BulkCardRequest) (*models.BulkCardResponse, error) {
+	response := models.BulkCardResponse{Results: make([]models.BulkCardResult, len(req.Operations))}
+	for i, op := range req.Operations {
+		response.Results[i] = models.BulkCardResult{Index: i, Op: op.Op, CardID: op.CardID, Status: models.BulkResultSkipped}
+	}
+
+	for i, op := range req.Operations {
+		if err := s.authorizeBulkOperation(boardID, actorID, op); err != nil {
+			response.Results[i].Status = models.BulkResultFailed
+			response.Results[i].Error = err.Error()
+			return &response, nil
+		}
+	}
+
+	announcements := make([]func() (*models.Card, error), len(req.Operations))
+	failed := -1
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for i, op := range req.Operations {
+			deletedLater := slices.ContainsFunc(req.Operations[i+1:], func(later models.BulkCardOperation) bool {
+				return later.Op == models.BulkOpDelete && later.CardID == op.CardID
+			})
+			announce, err := s.applyBulkOperation(tx, actorID, op, deletedLater)
+			if err != nil {
+				failed = i
+				response.Results[i].Status = models.BulkResultFailed
+				response.Results[i].Error = err.Error()
+				return err
+			}
+			announcements[i] = announce
+			response.Results[i].Status = models.BulkResultApplied
+		}
+		return nil
+	})
+	if failed >= 0 {
+		for i := range failed {
+			response.Results[i].Status = models.BulkResultRolledBack
+		}
+		return &response, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply bulk operations: %w", err)
+	}
+
+	// The batch is committed, so an announcement that fails is only logged
+	log.Printf("Bulk update on board %s: %d operations by user %s\n", boardID, len(req.Operations), actorID)
+	response.Applied = true
+	for i, announce := range announcements {
+		card, err := announce()
+		if err != nil {
+			log.Printf("Failed to announce bulk operation %d (%s) on card %s: %v\n", i, req.Operations[i].Op, req.Operations[i].CardID, err)
+			continue
+		}
+		response.Results[i].Card = card
+	}
+	return &response, nil
+}
+
+// authorizeBulkOperation checks that the operation's card is on the board and
+// that the actor could make the same change through the card's own endpoint,
+// including write access to the list a move goes to. It runs before the
+// batch's transaction, as authorizeAll requires.
+func (s *CardService) authorizeBulkOperation(boardID, actorID string, op models.BulkCardOperation) error {
+	path, err := s.hierarchyService.ResolvePath(models.ResourcePath{CardID: op.CardID})
+	if err != nil || path.BoardID != boardID {
+		return errors.New("card not found on this board")
+	}
+
+	action := models.ActionWrite
+	if op.Op == models.BulkOpDelete {
+		action = models.ActionDelete
+	}
+	checks := []permissionCheck{{op.CardID, action, op.Op + " this card", map[string]any{"bulk_op": op.Op}}}
+	if op.Op == models.BulkOpMove && op.ListID != "" && op.ListID != path.ListID {
+		checks = append(checks, permissionCheck{op.ListID, models.ActionWrite, "move cards into the destination list", map[string]any{"move_card": op.CardID}})
+	}
+	return authorizeAll(actorID, checks...)
+}
+
+// applyBulkOperation makes one operation's change inside tx and returns how
+// to announce it once the transaction has committed. When a later operation
+// of the batch deletes the card, the announcement reads it from the trash and
+// fires no automations.
+func (s *CardService) applyBulkOperation(tx *gorm.DB, actorID string, op models.BulkCardOperation, deletedLater bool) (func() (*models.Card, error), error) {
+	var card models.Card
+	err := tx.Preload("Labels").Preload("Assignees").First(&card, "id = ?", op.CardID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("card not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve card: %w", err)
+	}
+	if err := claimVersion(tx, &card, &card.Version, op.Version); err != nil {
+		return nil, err
+	}
+
+	reload := func() (*models.Card, error) {
+		if deletedLater {
+			return s.getTrashedCard(card.ID)
+		}
+		return s.GetCardByID(card.ID)
+	}
+	publish := func(eventType string) func() (*models.Card, error) {
+		return func() (*models.Card, error) {
+			updated, err := reload()
+			if err != nil {
+				return nil, err
+			}
+			publishListEvent(updated.ListID, eventType, actorID, updated)
+			return updated, nil
+		}
+	}
+	afterChange := func(updated *models.Card, events ...automationEvent) (*models.Card, error) {
+		if deletedLater {
+			return updated, nil
+		}
+		return s.afterChange(updated, events...)
+	}
+
+	switch op.Op {
+	case models.BulkOpMove:
+		toListID := card.ListID
+		if op.ListID != "" {
+			toListID = op.ListID
+		}
+		position := math.MaxInt
+		if op.Position != nil {
+			position = *op.Position
+		}
+		move, err := s.checkMove(tx, &card, toListID, actorID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.applyMove(tx, &card, move, position, actorID); err != nil {
+			return nil, err
+		}
+		return func() (*models.Card, error) {
+			if err := s.announceMove(move, actorID); err != nil {
+				return nil, err
+			}
+			moved, err := reload()
+			if err != nil {
+				return nil, err
+			}
+			if moved, err = afterChange(moved, move.automationEvents()...); err != nil {
+				return nil, err
+			}
+			moved.WIPLimitExceeded = move.wipLimitExceeded
+			return moved, nil
+		}, nil
+
+	case models.BulkOpSetLabels:
+		added, err := s.setLabels(tx, &card, op.LabelIDs, actorID)
+		if err != nil {
+			return nil, err
+		}
+		return func() (*models.Card, error) {
+			updated, err := publish(models.EventCardLabelsChanged)()
+			if err != nil {
+				return nil, err
+			}
+			return afterChange(updated, labelsAdded(card.ID, added)...)
+		}, nil
+
+	case models.BulkOpSetDueDate:
+		if !op.DueDate.Set {
+			return nil, errors.New("invalid operation: set_due_date needs a due_date, or null to clear it")
+		}
+		if err := s.setDueDate(tx, &card, op.DueDate.Ptr(), actorID); err != nil {
+			return nil, err
+		}
+		return publish(models.EventCardUpdated), nil
+
+	case models.BulkOpAssign:
+		if err := s.setAssignees(tx, &card, op.UserIDs, actorID); err != nil {
+			return nil, err
+		}
+		return publish(models.EventCardAssigneesChanged), nil
+
+	case models.BulkOpArchive:
+		if err := s.archiveCard(tx, &card, actorID); err != nil {
+			return nil, err
+		}
+		return publish(models.EventCardArchived), nil
+
+	case models.BulkOpDelete:
+		if err := s.trashCard(tx, &card, actorID); err != nil {
+			return nil, err
+		}
+		return func() (*models.Card, error) {
+			publishListEvent(card.ListID, models.EventCardDeleted, actorID, models.CardDeletedEvent{CardID: card.ID, ListID: card.ListID})
+			return nil, nil
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid operation: unknown op %s", op.Op)
+}
+
+// getTrashedCard loads a card in the trash with its labels and users.
+func (s *CardService) getTrashedCard(cardID string) (*models.Card, error) {
+	var card models.Card
+	result := database.DB.Unscoped().Preload("Labels", func(db *gorm.DB) *gorm.DB {
+		return db.Order("labels.name ASC")
+	}).Preload("Assignees").Preload("Watchers").First(&card, "id = ?", cardID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("card not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve card: %w", result.Error)
+	}
+	return &card, nil
+}
+
+// setDueDate sets or, given nil, clears the card's due date inside tx.
+func (s *CardService) setDueDate(tx *gorm.DB, card *models.Card, dueDate *time.Time, actorID string) error {
+	before := cardFields(card)
+	card.DueDate = dueDate
+	card.UpdatedAt = time.Now()
+	if err := tx.Model(card).UpdateColumns(map[string]any{"due_date": card.DueDate, "updated_at": card.UpdatedAt}).Error; err != nil {
+		return fmt.Errorf("failed to update due date: %w", err)
+	}
+	return recordActivity(tx, actorID, card, models.ActivityCardUpdated, before, cardFields(card))
+}
+
+// setAssignees replaces the card's assignees inside tx. Every user must be an
+// accepted member of the card's organization.
+func (s *CardService) setAssignees(tx *gorm.DB, card *models.Card, ids []string, actorID string) error {
+	path, err := s.hierarchyService.resolvePath(tx, models.ResourcePath{CardID: card.ID})
+	if err != nil {
+		return err
+	}
+
+	users := make([]*models.User, 0, len(ids))
+	for _, id := range slices.Compact(slices.Sorted(slices.Values(ids))) {
+		member, err := s.memberService.getAcceptedMember(tx, path.OrganizationID, id)
+		if err != nil {
+			return err
+		}
+		users = append(users, member.User)
+	}
+
+	before := userIDs(card.Assignees)
+	if err := tx.Model(card).Association("Assignees").Replace(users); err != nil {
+		return fmt.Errorf("failed to set assignees: %w", err)
+	}
+	return recordCardUsers(tx, actorID, card, "Assignees", before, userIDs(users))
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"kanban-app/api/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBulkUpdate(t *testing.T) {
+	b := newTestBoard(t)
+	todo := addTestList(t, b, "To Do")
+	done := addTestList(t, b, "Done")
+	first := addTestCard(t, b, todo, "First")
+	second := addTestCard(t, b, todo, "Second")
+
+	otherBoard := newTestBoardIn(t, b)
+	elsewhere := addTestCard(t, b, addTestList(t, otherBoard, "Elsewhere"), "Elsewhere")
+
+	due := models.Optional[time.Time]{Set: true, Value: time.Now().Add(24 * time.Hour)}
+	tests := []struct {
+		name        string
+		ops         []models.BulkCardOperation
+		wantApplied bool
+		wantStatus  []string
+		wantError   string
+		// wantList is where the first card is left
+		wantList string
+	}{
+		{
+			name: "operation on a card of another board",
+			ops: []models.BulkCardOperation{
+				{Op: models.BulkOpMove, CardID: first, ListID: done},
+				{Op: models.BulkOpArchive, CardID: elsewhere},
+				{Op: models.BulkOpArchive, CardID: second},
+			},
+			wantStatus: []string{models.BulkResultSkipped, models.BulkResultFailed, models.BulkResultSkipped},
+			wantError:  "card not found on this board",
+			wantList:   todo,
+		},
+		{
+			name: "invalid operation rolls back the ones before it",
+			ops: []models.BulkCardOperation{
+				{Op: models.BulkOpMove, CardID: first, ListID: done},
+				{Op: models.BulkOpSetDueDate, CardID: second},
+				{Op: models.BulkOpArchive, CardID: second},
+			},
+			wantStatus: []string{models.BulkResultRolledBack, models.BulkResultFailed, models.BulkResultSkipped},
+			wantError:  "set_due_date needs a due_date",
+			wantList:   todo,
+		},
+		{
+			name: "stale version",
+			ops: []models.BulkCardOperation{
+				{Op: models.BulkOpMove, CardID: first, ListID: done},
+				{Op: models.BulkOpArchive, CardID: second, Version: 5},
+			},
+			wantStatus: []string{models.BulkResultRolledBack, models.BulkResultFailed},
+			wantError:  "version mismatch",
+			wantList:   todo,
+		},
+		{
+			name: "every operation applied",
+			ops: []models.BulkCardOperation{
+				{Op: models.BulkOpMove, CardID: first, ListID: done},
+				{Op: models.BulkOpSetDueDate, CardID: second, DueDate: due},
+				{Op: models.BulkOpArchive, CardID: second},
+			},
+			wantApplied: true,
+			wantStatus:  []string{models.BulkResultApplied, models.BulkResultApplied, models.BulkResultApplied},
+			wantList:    done,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := NewCardService().BulkUpdate(b.BoardID, b.OwnerID, models.BulkCardRequest{Operations: tt.ops})
+			if err != nil {
+				t.Fatalf("BulkUpdate: %v", err)
+			}
+			if response.Applied != tt.wantApplied {
+				t.Errorf("applied = %v, want %v", response.Applied, tt.wantApplied)
+			}
+			if len(response.Results) != len(tt.wantStatus) {
+				t.Fatalf("got %d results, want %d", len(response.Results), len(tt.wantStatus))
+			}
+			for i, result := range response.Results {
+				if result.Index != i || result.CardID != tt.ops[i].CardID {
+					t.Errorf("result %d is for operation %d on card %s", i, result.Index, result.CardID)
+				}
+				if result.Status != tt.wantStatus[i] {
+					t.Errorf("result %d status = %q, want %q", i, result.Status, tt.wantStatus[i])
+				}
+				if result.Status == models.BulkResultFailed && !strings.Contains(result.Error, tt.wantError) {
+					t.Errorf("result %d error = %q, want it to contain %q", i, result.Error, tt.wantError)
+				}
+				if result.Status == models.BulkResultApplied && result.Card == nil {
+					t.Errorf("result %d has no card", i)
+				}
+			}
+			if got := loadTestCard(t, first).ListID; got != tt.wantList {
+				t.Errorf("first card is in list %s, want %s", got, tt.wantList)
+			}
+		})
+	}
+
+	card := loadTestCard(t, second)
+	if card.ArchivedAt == nil || card.DueDate == nil {
+		t.Errorf("second card archived at %v with due date %v, want both set", card.ArchivedAt, card.DueDate)
+	}
+}
+
+func TestBulkUpdateDeletesAfterOtherOperations(t *testing.T) {
+	b := newTestBoard(t)
+	todo := addTestList(t, b, "To Do")
+	done := addTestList(t, b, "Done")
+	card := addTestCard(t, b, todo, "Card")
+
+	// The batch is committed even though its earlier operations announce a
+	// card that is in the trash by then
+	response, err := NewCardService().BulkUpdate(b.BoardID, b.OwnerID, models.BulkCardRequest{Operations: []models.BulkCardOperation{
+		{Op: models.BulkOpMove, CardID: card, ListID: done},
+		{Op: models.BulkOpArchive, CardID: card},
+		{Op: models.BulkOpDelete, CardID: card},
+	}})
+	if err != nil {
+		t.Fatalf("BulkUpdate: %v", err)
+	}
+	if !response.Applied {
+		t.Fatalf("applied = false, results %+v", response.Results)
+	}
+	for i, result := range response.Results[:2] {
+		if result.Card == nil || result.Card.DeletedAt.Time.IsZero() {
+			t.Errorf("result %d card = %+v, want the card from the trash", i, result.Card)
+		}
+	}
+
+	stored := loadTestCard(t, card)
+	if stored.ListID != done || stored.ArchivedAt == nil || !stored.DeletedAt.Valid {
+		t.Errorf("card in list %s, archived at %v, deleted %v; want it moved, archived and deleted", stored.ListID, stored.ArchivedAt, stored.DeletedAt.Valid)
+	}
+}
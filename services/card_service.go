@@ -7,6 +7,11 @@ import (
 	"kanban-app/api/database"
 	"kanban-app/api/models"
 	"log"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,60 +19,105 @@ import (
 )
 
 type CardService struct {
-	labelService *LabelService
+	labelService      *LabelService
+	hierarchyService  *HierarchyService
+	memberService     *OrganizationMemberService
+	checklistService  *ChecklistService
+	automationService *AutomationService
 }
 
 func NewCardService() *CardService {
-	return &CardService{
-		labelService: NewLabelService(),
-	}
+	s := &CardService{
+		labelService:     NewLabelService(),
+		hierarchyService: NewHierarchyService(),
+		memberService:    NewOrganizationMemberService(),
+		checklistService: NewChecklistService(),
+	}
+	s.automationService = newAutomationService(s)
+	return s
 }
 
 func (s *CardService) CreateCard(listID, title, description string, dueDate *time.Time, userID string) (*models.Card, error) {
-	var maxPosition int
-	result := database.DB.Table("cards").Select("COALESCE(MAX(position), 0)").Where("list_id = ?", listID).Row().Scan(&maxPosition)
-	if result != nil && !errors.Is(result, gorm.ErrRecordNotFound) {
-		return nil, fmt.Errorf("failed to get max card position: %w", result)
-	}
-
 	newCard := models.Card{
 		ID:          uuid.New().String(),
 		ListID:      listID,
 		Title:       title,
 		Description: description,
-		Position:    maxPosition + 1,
 		DueDate:     dueDate,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
+	newCard.ListEnteredAt = &newCard.CreatedAt
 
-	createResult := database.DB.Create(&newCard)
-	if createResult.Error != nil {
-		return nil, fmt.Errorf("failed to create card: %w", createResult.Error)
+	// Rank the card after the last one in the same transaction that inserts
+	// it, so concurrent creates cannot both take the same place
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkListOpen(tx, listID); err != nil {
+			return err
+		}
+		rank, err := rankAt(tx, &models.Card{}, "list_id", listID, "", "", math.MaxInt)
+		if err != nil {
+			return err
+		}
+		newCard.Rank = rank
+		if newCard.WIPLimitExceeded, err = checkWIPLimit(tx, listID); err != nil {
+			return err
+		}
+		if err := tx.Create(&newCard).Error; err != nil {
+			return err
+		}
+		if err := enterList(tx, &newCard, listID, newCard.CreatedAt); err != nil {
+			return err
+		}
+		return recordActivity(tx, userID, &newCard, models.ActivityCardCreated, nil, cardFields(&newCard))
+	})
+	if err != nil {
+		if errors.Is(err, errWIPLimitReached) || errors.Is(err, errListArchived) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create card: %w", err)
 	}
 
-	log.Printf("Card created: %s in list %s at position %d\n", newCard.Title, newCard.ListID, newCard.Position)
+	log.Printf("Card created: %s in list %s at rank %s by user %s\n", newCard.Title, newCard.ListID, newCard.Rank, userID)
 
-	_, err := auth.NewAuthorizationService().AddPolicy(userID, newCard.ID, "owner")
+	_, err = auth.NewAuthorizationService().AddResourceParent(newCard.ID, listID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add policy for new card: %w", err)
+		return nil, fmt.Errorf("failed to link new card to list: %w", err)
 	}
 
+	publishListEvent(listID, models.EventCardCreated, userID, newCard)
 	return &newCard, nil
 }
 
-func (s *CardService) GetCardsByListID(listID string) ([]models.Card, error) {
+// GetCardsByListID returns the list's cards in rank order, leaving out
+// archived cards unless includeArchived is set.
+func (s *CardService) GetCardsByListID(listID string, includeArchived bool) ([]models.Card, error) {
+	query := database.DB.Where("list_id = ?", listID)
+	if !includeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
+
 	var cards []models.Card
-	result := database.DB.Where("list_id = ?", listID).Order("position ASC").Find(&cards)
+	result := query.Order(rankOrder).Find(&cards)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to retrieve cards: %w", result.Error)
 	}
+
+	refs := make([]*models.Card, len(cards))
+	for i := range cards {
+		refs[i] = &cards[i]
+	}
+	if err := s.checklistService.AttachProgress(refs); err != nil {
+		return nil, err
+	}
 	return cards, nil
 }
 
 func (s *CardService) GetCardByID(cardID string) (*models.Card, error) {
 	var card models.Card
-	result := database.DB.First(&card, "id = ?", cardID)
+	result := database.DB.Preload("Labels", func(db *gorm.DB) *gorm.DB {
+		return db.Order("labels.name ASC")
+	}).Preload("Assignees").Preload("Watchers").First(&card, "id = ?", cardID)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, errors.New("card not found")
@@ -77,116 +127,505 @@ func (s *CardService) GetCardByID(cardID string) (*models.Card, error) {
 	return &card, nil
 }
 
-func (s *CardService) UpdateCard(cardID string, updateReq models.UpdateCardRequest) (*models.Card, error) {
+func (s *CardService) UpdateCard(cardID string, version int, actorID string, updateReq models.UpdateCardRequest) (*models.Card, error) {
 	card, err := s.GetCardByID(cardID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update text fields
-	if updateReq.Title != "" {
-		card.Title = updateReq.Title
+	if updateReq.Title.Null {
+		return nil, errors.New("title cannot be null")
+	}
+
+	// A position moves the card within its list, or within the list given
+	// alongside it; a list alone moves the card to the end of that list
+	var move *cardMove
+	position := math.MaxInt
+	if updateReq.Position != nil || (updateReq.ListID != "" && updateReq.ListID != card.ListID) {
+		if updateReq.Position != nil {
+			if *updateReq.Position < 1 {
+				return nil, errors.New("invalid position: must be greater than 0")
+			}
+			position = *updateReq.Position
+		}
+		toListID := card.ListID
+		if updateReq.ListID != "" {
+			toListID = updateReq.ListID
+		}
+		if move, err = s.checkMove(database.DB, card, toListID, actorID); err != nil {
+			return nil, err
+		}
+	}
+
+	before := cardFields(card)
+	if updateReq.Title.Set {
+		card.Title = updateReq.Title.Value
+	}
+	// Clearing a text field stores an empty string
+	if updateReq.Description.Set {
+		card.Description = updateReq.Description.Value
 	}
-	if updateReq.Description != "" {
-		card.Description = updateReq.Description
+	if updateReq.Notes.Set {
+		card.Notes = updateReq.Notes.Value
 	}
-	if updateReq.DueDate != nil {
-		card.DueDate = updateReq.DueDate
+	if updateReq.DueDate.Set {
+		card.DueDate = updateReq.DueDate.Ptr()
 	}
 
 	card.UpdatedAt = time.Now()
-	if err := database.DB.Save(&card).Error; err != nil {
-		return nil, fmt.Errorf("failed to update card fields: %w", err)
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, card, &card.Version, version); err != nil {
+			return err
+		}
+		// Only the edited fields are written, leaving the card's placement and
+		// associations to the writers that own them
+		err := tx.Model(card).UpdateColumns(map[string]any{
+			"title":       card.Title,
+			"description": card.Description,
+			"notes":       card.Notes,
+			"due_date":    card.DueDate,
+			"updated_at":  card.UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := recordActivity(tx, actorID, card, models.ActivityCardUpdated, before, cardFields(card)); err != nil {
+			return err
+		}
+		if move == nil {
+			return nil
+		}
+		return s.applyMove(tx, card, move, position, actorID)
+	})
+	if err != nil {
+		if errors.Is(err, errVersionMismatch) || errors.Is(err, errWIPLimitReached) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update card: %w", err)
 	}
 
-	// Handle moving the card
-	if updateReq.Position != nil {
-		// If ListID is not provided, use the card's current list ID
-		newListID := card.ListID
-		if updateReq.ListID != "" {
-			newListID = updateReq.ListID
-		}
-		if err := s.MoveCard(cardID, newListID, *updateReq.Position); err != nil {
+	if move != nil {
+		if err := s.announceMove(move, actorID); err != nil {
 			return nil, err
 		}
 	}
 
-	return s.GetCardByID(cardID)
+	var updated *models.Card
+	if _, changed := diffFields(before, cardFields(card)); len(changed) == 0 {
+		updated, err = s.GetCardByID(cardID)
+	} else {
+		updated, err = s.reloadAndPublish(cardID, models.EventCardUpdated, actorID)
+	}
+	if err != nil || move == nil {
+		return updated, err
+	}
+	if updated, err = s.afterChange(updated, move.automationEvents()...); err != nil {
+		return nil, err
+	}
+	updated.WIPLimitExceeded = move.wipLimitExceeded
+	return updated, nil
 }
 
-func (s *CardService) DeleteCard(cardID string) error {
+// DeleteCard moves the card to the trash. It keeps its rank, so restoring it
+// puts it back between the same neighbours.
+func (s *CardService) DeleteCard(cardID string, version int, actorID string) (*models.DeletionSummary, error) {
 	tx := database.DB.Begin()
 	if tx.Error != nil {
-		return fmt.Errorf("failed to start transaction: %w", tx.Error)
+		return nil, fmt.Errorf("failed to start transaction: %w", tx.Error)
 	}
 
 	var cardToDelete models.Card
 	if err := tx.First(&cardToDelete, "id = ?", cardID).Error; err != nil {
 		tx.Rollback()
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("card not found")
+			return nil, errors.New("card not found or already deleted")
 		}
-		return fmt.Errorf("failed to find card to delete: %w", err)
+		return nil, fmt.Errorf("failed to find card to delete: %w", err)
 	}
 
-	if err := tx.Delete(&models.Card{}, "id = ?", cardID).Error; err != nil {
+	if err := claimVersion(tx, &cardToDelete, &cardToDelete.Version, version); err != nil {
 		tx.Rollback()
-		return fmt.Errorf("failed to delete card: %w", err)
+		return nil, err
 	}
 
-	if err := tx.Model(&models.Card{}).Where("list_id = ? AND position > ?", cardToDelete.ListID, cardToDelete.Position).Update("position", gorm.Expr("position - 1")).Error; err != nil {
+	if err := s.trashCard(tx, &cardToDelete, actorID); err != nil {
 		tx.Rollback()
-		return fmt.Errorf("failed to update positions of subsequent cards: %w", err)
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
 	}
 
-	log.Printf("Card deleted: ID %s\n", cardID)
-	return tx.Commit().Error
+	log.Printf("Card moved to trash: ID %s\n", cardID)
+	publishListEvent(cardToDelete.ListID, models.EventCardDeleted, actorID, models.CardDeletedEvent{
+		CardID: cardID,
+		ListID: cardToDelete.ListID,
+	})
+	return &models.DeletionSummary{Cards: 1}, nil
 }
 
-func (s *CardService) MoveCard(cardID string, newListID string, newPosition int) error {
-	tx := database.DB.Begin()
-	if tx.Error != nil {
-		return fmt.Errorf("failed to start transaction: %w", tx.Error)
+// trashCard soft-deletes the card inside tx and records it in the history.
+func (s *CardService) trashCard(tx *gorm.DB, card *models.Card, actorID string) error {
+	if err := tx.Delete(&models.Card{}, "id = ?", card.ID).Error; err != nil {
+		return fmt.Errorf("failed to delete card: %w", err)
 	}
 
+	before := cardFields(card)
+	maps.Copy(before, cardPlacement(card))
+	return recordActivity(tx, actorID, card, models.ActivityCardDeleted, before, nil)
+}
+
+// ArchiveCard takes the card off its list without deleting it. It keeps its
+// rank, so unarchiving it puts it back between the same neighbours.
+func (s *CardService) ArchiveCard(cardID string, version int, actorID string) (*models.Card, error) {
+	return s.changeArchived(cardID, version, actorID, s.archiveCard, models.EventCardArchived)
+}
+
+// UnarchiveCard puts an archived card back on its list.
+func (s *CardService) UnarchiveCard(cardID string, version int, actorID string) (*models.Card, error) {
+	return s.changeArchived(cardID, version, actorID, s.unarchiveCard, models.EventCardUnarchived)
+}
+
+func (s *CardService) changeArchived(cardID string, version int, actorID string, change func(*gorm.DB, *models.Card, string) error, eventType string) (*models.Card, error) {
 	var card models.Card
-	if err := tx.First(&card, "id = ?", cardID).Error; err != nil {
-		tx.Rollback()
-		return errors.New("card not found")
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&card, "id = ?", cardID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("card not found")
+			}
+			return fmt.Errorf("failed to retrieve card: %w", err)
+		}
+		if err := claimVersion(tx, &card, &card.Version, version); err != nil {
+			return err
+		}
+		return change(tx, &card, actorID)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	oldListID := card.ListID
-	oldPosition := card.Position
+	log.Printf("Card %s: ID %s by user %s\n", strings.TrimPrefix(eventType, "card."), cardID, actorID)
+	updated, err := s.reloadAndPublish(cardID, eventType, actorID)
+	if err != nil {
+		return nil, err
+	}
+	updated.WIPLimitExceeded = card.WIPLimitExceeded
+	return updated, nil
+}
 
-	if oldListID == newListID && oldPosition == newPosition {
-		tx.Rollback()
-		return nil // No change
+// ArchiveListCards archives every card in the list that isn't archived yet,
+// in one transaction.
+func (s *CardService) ArchiveListCards(listID, actorID string) (*models.ArchivedCards, error) {
+	result := models.ArchivedCards{ListID: listID, CardIDs: []string{}}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var cards []models.Card
+		if err := tx.Where("list_id = ? AND archived_at IS NULL", listID).Order(rankOrder).Find(&cards).Error; err != nil {
+			return fmt.Errorf("failed to retrieve cards: %w", err)
+		}
+		for i := range cards {
+			if err := claimVersion(tx, &cards[i], &cards[i].Version, AnyVersion); err != nil {
+				return err
+			}
+			if err := s.archiveCard(tx, &cards[i], actorID); err != nil {
+				return err
+			}
+			result.CardIDs = append(result.CardIDs, cards[i].ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive cards: %w", err)
 	}
 
-	// Decrement positions in the old list
-	if err := tx.Model(&models.Card{}).Where("list_id = ? AND position > ?", oldListID, oldPosition).Update("position", gorm.Expr("position - 1")).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to shift cards in old list: %w", err)
+	result.Archived = len(result.CardIDs)
+	log.Printf("Archived %d cards in list %s by user %s\n", result.Archived, listID, actorID)
+	for _, cardID := range result.CardIDs {
+		if _, err := s.reloadAndPublish(cardID, models.EventCardArchived, actorID); err != nil {
+			return nil, err
+		}
 	}
+	return &result, nil
+}
 
-	// Increment positions in the new list
-	if err := tx.Model(&models.Card{}).Where("list_id = ? AND position >= ?", newListID, newPosition).Update("position", gorm.Expr("position + 1")).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to shift cards in new list: %w", err)
+// archiveCard takes the card off the board inside tx without deleting it.
+func (s *CardService) archiveCard(tx *gorm.DB, card *models.Card, actorID string) error {
+	if card.ArchivedAt != nil {
+		return errors.New("card is already archived")
+	}
+
+	now := time.Now()
+	card.ArchivedAt = &now
+	card.UpdatedAt = now
+	if err := tx.Model(card).UpdateColumns(map[string]any{"archived_at": now, "updated_at": now}).Error; err != nil {
+		return fmt.Errorf("failed to archive card: %w", err)
+	}
+	return recordActivity(tx, actorID, card, models.ActivityCardArchived, nil, map[string]any{"archived_at": now})
+}
+
+// unarchiveCard puts an archived card back on the board inside tx. The card
+// returns to its list as if entering it, so the list must be open and the
+// card counts towards its WIP limit again.
+func (s *CardService) unarchiveCard(tx *gorm.DB, card *models.Card, actorID string) error {
+	if card.ArchivedAt == nil {
+		return errors.New("card is not archived")
+	}
+	if err := checkListOpen(tx, card.ListID); err != nil {
+		return err
 	}
+	exceeded, err := checkWIPLimit(tx, card.ListID)
+	if err != nil {
+		return err
+	}
+	card.WIPLimitExceeded = exceeded
 
-	card.ListID = newListID
-	card.Position = newPosition
+	before := map[string]any{"archived_at": *card.ArchivedAt}
+	card.ArchivedAt = nil
 	card.UpdatedAt = time.Now()
-	if err := tx.Save(&card).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to update card position: %w", err)
+	if err := tx.Model(card).UpdateColumns(map[string]any{"archived_at": nil, "updated_at": card.UpdatedAt}).Error; err != nil {
+		return fmt.Errorf("failed to unarchive card: %w", err)
+	}
+	return recordActivity(tx, actorID, card, models.ActivityCardUnarchived, before, nil)
+}
+
+// MoveCardToList moves the card to a 1-based position in a list on any board
+// of its organization the actor can write to, or to the end of the list when
+// no position is given. It returns the new ordering of the lists the card
+// left and entered.
+func (s *CardService) MoveCardToList(cardID string, version int, actorID string, req models.MoveCardRequest) (*models.CardMoveResult, error) {
+	card, err := s.GetCardByID(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	move, err := s.checkMove(database.DB, card, req.ListID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	position := math.MaxInt
+	if req.Position != nil {
+		position = *req.Position
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, card, &card.Version, version); err != nil {
+			return err
+		}
+		return s.applyMove(tx, card, move, position, actorID)
+	})
+	if err != nil {
+		if errors.Is(err, errVersionMismatch) || errors.Is(err, errWIPLimitReached) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to move card: %w", err)
+	}
+
+	if err := s.announceMove(move, actorID); err != nil {
+		return nil, err
+	}
+	s.automationService.dispatch(move.automationEvents()...)
+
+	var result models.CardMoveResult
+	if result.Card, err = s.GetCardByID(cardID); err != nil {
+		return nil, err
+	}
+	result.Card.WIPLimitExceeded = move.wipLimitExceeded
+	if result.ToList, err = s.listWithCards(move.toListID); err != nil {
+		return nil, err
+	}
+	if move.fromListID != move.toListID {
+		if result.FromList, err = s.listWithCards(move.fromListID); err != nil {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
+
+// cardMove is a card's move into a list, checked before the transaction
+// that makes it and announced once that has committed.
+type cardMove struct {
+	cardID      string
+	fromListID  string
+	fromBoardID string
+	toListID    string
+	toBoardID   string
+	rank        string
+	moved       bool
+	// wipLimitExceeded is set when the card entered a list already at its
+	// WIP limit, which the board's warn policy allows.
+	wipLimitExceeded bool
+}
+
+// checkMove makes sure the card may move into toListID: the list must exist
+// outside the trash on a board of the card's organization, and when it is a
+// different list the actor needs write access to it as well as to the card.
+// Reads go through db, which may be the transaction making the move.
+func (s *CardService) checkMove(db *gorm.DB, card *models.Card, toListID, actorID string) (*cardMove, error) {
+	from, err := s.hierarchyService.resolvePath(db, models.ResourcePath{ListID: card.ListID})
+	if err != nil {
+		return nil, err
+	}
+	if toListID == card.ListID {
+		return &cardMove{cardID: card.ID, fromListID: card.ListID, fromBoardID: from.BoardID, toListID: toListID, toBoardID: from.BoardID}, nil
+	}
+
+	to, err := s.hierarchyService.resolvePath(db, models.ResourcePath{ListID: toListID})
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errors.New("destination list not found")
+		}
+		return nil, err
+	}
+	if to.OrganizationID != from.OrganizationID {
+		return nil, errors.New("cards cannot move to a board of another organization")
+	}
+	if err := checkListOpen(db, toListID); err != nil {
+		return nil, err
+	}
+
+	err = authorize(actorID, toListID, models.ActionWrite, "move cards into the destination list", map[string]any{"move_card": card.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &cardMove{cardID: card.ID, fromListID: card.ListID, fromBoardID: from.BoardID, toListID: toListID, toBoardID: to.BoardID}, nil
+}
+
+// errListArchived refuses a card entering an archived list, where it would
+// drop out of sight without being archived itself.
+var errListArchived = errors.New("list is archived: cards cannot be added to it")
+
+// checkListOpen makes sure cards may enter the list.
+func checkListOpen(db *gorm.DB, listID string) error {
+	var list models.List
+	if err := db.Select("archived_at").First(&list, "id = ?", listID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("list not found")
+		}
+		return fmt.Errorf("failed to retrieve list: %w", err)
+	}
+	if list.ArchivedAt != nil {
+		return errListArchived
+	}
+	return nil
+}
+
+// applyMove ranks the card at the 1-based position in the destination list,
+// or at the end if the position is past it. Only the card's own row changes.
+// A card entering another list must fit under its WIP limit. Board labels
+// don't carry over to another board, so they are removed.
+func (s *CardService) applyMove(tx *gorm.DB, card *models.Card, move *cardMove, position int, actorID string) error {
+	rank, err := rankAt(tx, &models.Card{}, "list_id", move.toListID, card.ID, card.Rank, position)
+	if err != nil {
+		return fmt.Errorf("failed to rank card: %w", err)
+	}
+	if move.toListID == card.ListID && rank == card.Rank {
+		return nil // No change
+	}
+
+	before := cardPlacement(card)
+	now := time.Now()
+	columns := map[string]any{"list_id": move.toListID, "rank": rank, "updated_at": now}
+	if move.toListID != card.ListID {
+		if move.wipLimitExceeded, err = checkWIPLimit(tx, move.toListID); err != nil {
+			return err
+		}
+		if err := enterList(tx, card, move.toListID, now); err != nil {
+			return err
+		}
+		card.ListEnteredAt = &now
+		columns["list_entered_at"] = now
+	}
+	card.ListID = move.toListID
+	card.Rank = rank
+	card.UpdatedAt = now
+	err = tx.Model(card).UpdateColumns(columns).Error
+	if err != nil {
+		return fmt.Errorf("failed to update card rank: %w", err)
+	}
+	if err := recordActivity(tx, actorID, card, models.ActivityCardMoved, before, cardPlacement(card)); err != nil {
+		return err
+	}
+	move.rank = rank
+	move.moved = true
+
+	if move.toBoardID == move.fromBoardID {
+		return nil
+	}
+	var dropped []*models.Label
+	for _, label := range card.Labels {
+		if label.BoardID != "" && label.BoardID != move.toBoardID {
+			dropped = append(dropped, label)
+		}
+	}
+	if len(dropped) == 0 {
+		return nil
+	}
+	labelsBefore := labelNames(card.Labels)
+	if err := tx.Model(card).Association("Labels").Delete(dropped); err != nil {
+		return fmt.Errorf("failed to remove board labels: %w", err)
+	}
+	return recordActivity(tx, actorID, card, models.ActivityLabelsChanged,
+		map[string]any{"labels": labelsBefore}, map[string]any{"labels": labelNames(card.Labels)})
+}
+
+// enterList records the card entering listID at the given time, ending its
+// stay in the list it was in.
+func enterList(tx *gorm.DB, card *models.Card, listID string, at time.Time) error {
+	err := tx.Model(&models.CardListStay{}).Where("card_id = ? AND exited_at IS NULL", card.ID).Update("exited_at", at).Error
+	if err != nil {
+		return fmt.Errorf("failed to end list stay: %w", err)
+	}
+	stay := models.CardListStay{ID: uuid.New().String(), CardID: card.ID, ListID: listID, EnteredAt: at}
+	if err := tx.Create(&stay).Error; err != nil {
+		return fmt.Errorf("failed to record list stay: %w", err)
+	}
+	return nil
+}
+
+// announceMove re-parents a moved card so it inherits grants from its new
+// list and tells the boards involved about the move.
+func (s *CardService) announceMove(move *cardMove, actorID string) error {
+	if !move.moved {
+		return nil
+	}
+
+	if move.fromListID != move.toListID {
+		authService := auth.NewAuthorizationService()
+		if _, err := authService.RemoveResourceParent(move.cardID, move.fromListID); err != nil {
+			return fmt.Errorf("failed to unlink card from old list: %w", err)
+		}
+		if _, err := authService.AddResourceParent(move.cardID, move.toListID); err != nil {
+			return fmt.Errorf("failed to link card to new list: %w", err)
+		}
 	}
 
-	return tx.Commit().Error
+	event := models.CardMovedEvent{
+		CardID:     move.cardID,
+		FromListID: move.fromListID,
+		ToListID:   move.toListID,
+		Rank:       move.rank,
+	}
+	publishBoardEvent(move.toBoardID, models.EventCardMoved, actorID, event)
+	if move.fromBoardID != move.toBoardID {
+		publishBoardEvent(move.fromBoardID, models.EventCardMoved, actorID, event)
+	}
+	return nil
+}
+
+// listWithCards loads a list with its unarchived cards in rank order.
+func (s *CardService) listWithCards(listID string) (*models.List, error) {
+	list := models.List{Cards: []*models.Card{}}
+	err := database.DB.Preload("Cards", func(db *gorm.DB) *gorm.DB {
+		return db.Where("cards.archived_at IS NULL").Order("cards.rank ASC, cards.id ASC")
+	}).First(&list, "id = ?", listID).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load list: %w", err)
+	}
+	return &list, nil
 }
 
-func (s *CardService) AddLabelToCard(cardID, labelID string) (*models.Card, error) {
+func (s *CardService) AddLabelToCard(cardID string, version int, labelID, actorID string) (*models.Card, error) {
 	card, err := s.GetCardByID(cardID)
 	if err != nil {
 		return nil, err
@@ -197,6 +636,14 @@ func (s *CardService) AddLabelToCard(cardID, labelID string) (*models.Card, erro
 		return nil, err
 	}
 
+	path, err := s.hierarchyService.ResolvePath(models.ResourcePath{CardID: cardID})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.labelService.checkLabelScope(label, path.OrganizationID, path.BoardID); err != nil {
+		return nil, err
+	}
+
 	// Check if label is already associated
 	for _, l := range card.Labels {
 		if l.ID == label.ID {
@@ -204,20 +651,34 @@ func (s *CardService) AddLabelToCard(cardID, labelID string) (*models.Card, erro
 		}
 	}
 
-	if err := database.DB.Model(&card).Association("Labels").Append(label); err != nil {
+	before := labelNames(card.Labels)
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, card, &card.Version, version); err != nil {
+			return err
+		}
+		if err := tx.Model(card).Association("Labels").Append(label); err != nil {
+			return err
+		}
+		return recordActivity(tx, actorID, card, models.ActivityLabelsChanged,
+			map[string]any{"labels": before}, map[string]any{"labels": labelNames(card.Labels)})
+	})
+	if err != nil {
+		if errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to add label to card: %w", err)
 	}
 
 	// Reload the card to include the newly associated label
-	updatedCard, err := s.GetCardByID(cardID)
+	updatedCard, err := s.reloadAndPublish(cardID, models.EventCardLabelsChanged, actorID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to reload card after adding label: %w", err)
 	}
 
-	return updatedCard, nil
+	return s.afterChange(updatedCard, labelsAdded(cardID, []string{label.ID})...)
 }
 
-func (s *CardService) RemoveLabelFromCard(cardID, labelID string) (*models.Card, error) {
+func (s *CardService) RemoveLabelFromCard(cardID string, version int, labelID, actorID string) (*models.Card, error) {
 	card, err := s.GetCardByID(cardID)
 	if err != nil {
 		return nil, err
@@ -241,12 +702,26 @@ func (s *CardService) RemoveLabelFromCard(cardID, labelID string) (*models.Card,
 		return nil, errors.New("label not associated with this card")
 	}
 
-	if err := database.DB.Model(&card).Association("Labels").Delete(label); err != nil {
+	before := labelNames(card.Labels)
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, card, &card.Version, version); err != nil {
+			return err
+		}
+		if err := tx.Model(card).Association("Labels").Delete(label); err != nil {
+			return err
+		}
+		return recordActivity(tx, actorID, card, models.ActivityLabelsChanged,
+			map[string]any{"labels": before}, map[string]any{"labels": labelNames(card.Labels)})
+	})
+	if err != nil {
+		if errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to remove label from card: %w", err)
 	}
 
 	// Reload the card to reflect the removal of the label
-	updatedCard, err := s.GetCardByID(cardID)
+	updatedCard, err := s.reloadAndPublish(cardID, models.EventCardLabelsChanged, actorID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to reload card after removing label: %w", err)
 	}
@@ -254,3 +729,276 @@ func (s *CardService) RemoveLabelFromCard(cardID, labelID string) (*models.Card,
 	return updatedCard, nil
 }
 
+// SetCardLabels replaces all labels on a card with the given set.
+func (s *CardService) SetCardLabels(cardID string, version int, labelIDs []string, actorID string) (*models.Card, error) {
+	card, err := s.GetCardByID(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	var added []string
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, card, &card.Version, version); err != nil {
+			return err
+		}
+		added, err = s.setLabels(tx, card, labelIDs, actorID)
+		return err
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "label not found") || strings.Contains(err.Error(), "not available on this board") || errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to set card labels: %w", err)
+	}
+
+	log.Printf("Labels set on card %s: %d labels\n", cardID, len(labelIDs))
+	card, err = s.reloadAndPublish(cardID, models.EventCardLabelsChanged, actorID)
+	if err != nil {
+		return nil, err
+	}
+	return s.afterChange(card, labelsAdded(cardID, added)...)
+}
+
+// setLabels replaces the card's labels inside tx and returns the IDs of the
+// labels it didn't have before. Every label must be available on the board
+// the card is on at that point in the transaction.
+func (s *CardService) setLabels(tx *gorm.DB, card *models.Card, labelIDs []string, actorID string) ([]string, error) {
+	labels := []*models.Label{}
+	if len(labelIDs) > 0 {
+		if err := tx.Where("id IN ?", labelIDs).Find(&labels).Error; err != nil {
+			return nil, fmt.Errorf("failed to retrieve labels: %w", err)
+		}
+	}
+	path, err := s.hierarchyService.resolvePath(tx, models.ResourcePath{CardID: card.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		if err := s.labelService.checkLabelScope(l, path.OrganizationID, path.BoardID); err != nil {
+			return nil, err
+		}
+		found[l.ID] = true
+	}
+	for _, id := range labelIDs {
+		if !found[id] {
+			return nil, fmt.Errorf("label not found: %s", id)
+		}
+	}
+
+	had := make(map[string]bool, len(card.Labels))
+	for _, l := range card.Labels {
+		had[l.ID] = true
+	}
+	var added []string
+	for _, l := range labels {
+		if !had[l.ID] {
+			added = append(added, l.ID)
+		}
+	}
+
+	before := labelNames(card.Labels)
+	if err := tx.Model(card).Association("Labels").Replace(labels); err != nil {
+		return nil, err
+	}
+	err = recordActivity(tx, actorID, card, models.ActivityLabelsChanged,
+		map[string]any{"labels": before}, map[string]any{"labels": labelNames(labels)})
+	return added, err
+}
+
+// GetCardsByLabel returns the cards on the board that carry the label.
+// Archived cards and the cards of archived lists are left out unless
+// includeArchived is set.
+func (s *CardService) GetCardsByLabel(orgID, boardID, labelID string, includeArchived bool) ([]models.Card, error) {
+	label, err := s.labelService.GetLabelByID(labelID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.labelService.checkLabelScope(label, orgID, boardID); err != nil {
+		return nil, err
+	}
+
+	query := database.DB.Preload("Labels", func(db *gorm.DB) *gorm.DB {
+		return db.Order("labels.name ASC")
+	}).
+		Select("cards.*").
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Joins("JOIN card_labels ON card_labels.card_id = cards.id").
+		Where("lists.board_id = ? AND card_labels.label_id = ?", boardID, labelID)
+	if !includeArchived {
+		query = unarchivedCards(query)
+	}
+
+	var cards []models.Card
+	result := query.Order("lists.rank ASC, lists.id ASC, cards.rank ASC, cards.id ASC").Find(&cards)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve cards by label: %w", result.Error)
+	}
+	return cards, nil
+}
+
+func (s *CardService) AddAssignee(cardID string, version int, userID, actorID string) (*models.Card, error) {
+	return s.addCardUser(cardID, version, userID, actorID, "Assignees", "assigned to")
+}
+
+func (s *CardService) RemoveAssignee(cardID string, version int, userID, actorID string) (*models.Card, error) {
+	return s.removeCardUser(cardID, version, userID, actorID, "Assignees", "assigned to")
+}
+
+func (s *CardService) AddWatcher(cardID string, version int, userID, actorID string) (*models.Card, error) {
+	return s.addCardUser(cardID, version, userID, actorID, "Watchers", "watching")
+}
+
+func (s *CardService) RemoveWatcher(cardID string, version int, userID, actorID string) (*models.Card, error) {
+	return s.removeCardUser(cardID, version, userID, actorID, "Watchers", "watching")
+}
+
+// GetAssignedCards returns every live card assigned to the user, across all
+// boards, ordered by due date with undated cards last. Archived cards and the
+// cards of archived lists are left out unless includeArchived is set.
+func (s *CardService) GetAssignedCards(userID string, includeArchived bool) ([]models.Card, error) {
+	query := database.DB.Preload("Labels", func(db *gorm.DB) *gorm.DB {
+		return db.Order("labels.name ASC")
+	}).Preload("Assignees").
+		Select("cards.*").
+		Joins("JOIN card_assignees ON card_assignees.card_id = cards.id").
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Joins("JOIN boards ON boards.id = lists.board_id AND boards.deleted_at IS NULL").
+		Where("card_assignees.user_id = ?", userID)
+	if !includeArchived {
+		query = unarchivedCards(query)
+	}
+
+	var cards []models.Card
+	result := query.Order("cards.due_date IS NULL, cards.due_date ASC, cards.created_at ASC").Find(&cards)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve assigned cards: %w", result.Error)
+	}
+	return cards, nil
+}
+
+// unarchivedCards leaves out archived cards and the cards of archived lists
+// from a query joining cards with their lists.
+func unarchivedCards(query *gorm.DB) *gorm.DB {
+	return query.Where("cards.archived_at IS NULL AND lists.archived_at IS NULL")
+}
+
+// addCardUser links a user to a card through the named association. Only
+// accepted members of the organization owning the card can be linked.
+func (s *CardService) addCardUser(cardID string, version int, userID, actorID, association, relation string) (*models.Card, error) {
+	card, err := s.GetCardByID(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := s.hierarchyService.ResolvePath(models.ResourcePath{CardID: cardID})
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.memberService.GetAcceptedMember(path.OrganizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	users := card.Assignees
+	if association == "Watchers" {
+		users = card.Watchers
+	}
+	for _, u := range users {
+		if u.ID == userID {
+			return nil, fmt.Errorf("user already %s this card", relation)
+		}
+	}
+
+	before := userIDs(users)
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, card, &card.Version, version); err != nil {
+			return err
+		}
+		if err := tx.Model(card).Association(association).Append(member.User); err != nil {
+			return err
+		}
+		return recordCardUsers(tx, actorID, card, association, before, append(slices.Clone(before), userID))
+	})
+	if err != nil {
+		if errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to add user to card: %w", err)
+	}
+
+	log.Printf("User %s now %s card %s\n", userID, relation, cardID)
+	return s.reloadAndPublish(cardID, cardUsersEvent(association), actorID)
+}
+
+func (s *CardService) removeCardUser(cardID string, version int, userID, actorID, association, relation string) (*models.Card, error) {
+	card, err := s.GetCardByID(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	users := card.Assignees
+	if association == "Watchers" {
+		users = card.Watchers
+	}
+	var user *models.User
+	for _, u := range users {
+		if u.ID == userID {
+			user = u
+			break
+		}
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not %s this card", relation)
+	}
+
+	before := userIDs(users)
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, card, &card.Version, version); err != nil {
+			return err
+		}
+		if err := tx.Model(card).Association(association).Delete(user); err != nil {
+			return err
+		}
+		return recordCardUsers(tx, actorID, card, association, before, slices.DeleteFunc(slices.Clone(before), func(id string) bool { return id == userID }))
+	})
+	if err != nil {
+		if errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to remove user from card: %w", err)
+	}
+
+	log.Printf("User %s no longer %s card %s\n", userID, relation, cardID)
+	return s.reloadAndPublish(cardID, cardUsersEvent(association), actorID)
+}
+
+// reloadAndPublish returns the card as stored and announces the change on its
+// board.
+func (s *CardService) reloadAndPublish(cardID, eventType, actorID string) (*models.Card, error) {
+	card, err := s.GetCardByID(cardID)
+	if err != nil {
+		return nil, err
+	}
+	publishListEvent(card.ListID, eventType, actorID, card)
+	return card, nil
+}
+
+func cardUsersEvent(association string) string {
+	if association == "Watchers" {
+		return models.EventCardWatchersChanged
+	}
+	return models.EventCardAssigneesChanged
+}
+
+// recordCardUsers records a change to a card's assignees or watchers.
+func recordCardUsers(tx *gorm.DB, actorID string, card *models.Card, association string, before, after []string) error {
+	action, field := models.ActivityAssigneesChanged, "assignees"
+	if association == "Watchers" {
+		action, field = models.ActivityWatchersChanged, "watchers"
+	}
+	sort.Strings(after)
+	return recordActivity(tx, actorID, card, action, map[string]any{field: before}, map[string]any{field: after})
+}
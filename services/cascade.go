@@ -0,0 +1,326 @@
+package services
+
+import (
+	"fmt"
+	"kanban-app/api/auth"
+	"kanban-app/api/models"
+
+	"gorm.io/gorm"
+)
+
+// cascadeDeleter permanently removes resources together with everything
+// beneath them, including soft-deleted boards, lists and cards, in a single
+// transaction. Casbin policies live outside the transaction, so the IDs
+// of deleted resources are collected and their policies removed afterwards.
+type cascadeDeleter struct {
+	tx          *gorm.DB
+	summary     models.DeletionSummary
+	resourceIDs []string
+}
+
+func newCascadeDeleter(tx *gorm.DB) *cascadeDeleter {
+	return &cascadeDeleter{tx: tx}
+}
+
+func (d *cascadeDeleter) deleteOrganizations(orgIDs []string) error {
+	if len(orgIDs) == 0 {
+		return nil
+	}
+
+	var projectIDs []string
+	if err := d.tx.Model(&models.Project{}).Where("organization_id IN ?", orgIDs).Pluck("id", &projectIDs).Error; err != nil {
+		return fmt.Errorf("failed to find projects: %w", err)
+	}
+	if err := d.deleteProjects(projectIDs); err != nil {
+		return err
+	}
+
+	var labelIDs []string
+	if err := d.tx.Model(&models.Label{}).Where("organization_id IN ?", orgIDs).Pluck("id", &labelIDs).Error; err != nil {
+		return fmt.Errorf("failed to find labels: %w", err)
+	}
+	if err := d.deleteLabels(labelIDs); err != nil {
+		return err
+	}
+
+	var webhookIDs []string
+	if err := d.tx.Model(&models.Webhook{}).Where("organization_id IN ?", orgIDs).Pluck("id", &webhookIDs).Error; err != nil {
+		return fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	if err := d.deleteWebhooks(webhookIDs); err != nil {
+		return err
+	}
+
+	result := d.tx.Where("organization_id IN ?", orgIDs).Delete(&models.OrganizationMember{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete members: %w", result.Error)
+	}
+	d.summary.Members += result.RowsAffected
+
+	result = d.tx.Where("id IN ?", orgIDs).Delete(&models.Organization{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete organizations: %w", result.Error)
+	}
+	d.summary.Organizations += result.RowsAffected
+	d.resourceIDs = append(d.resourceIDs, orgIDs...)
+	return nil
+}
+
+func (d *cascadeDeleter) deleteProjects(projectIDs []string) error {
+	if len(projectIDs) == 0 {
+		return nil
+	}
+
+	var boardIDs []string
+	if err := d.tx.Unscoped().Model(&models.Board{}).Where("project_id IN ?", projectIDs).Pluck("id", &boardIDs).Error; err != nil {
+		return fmt.Errorf("failed to find boards: %w", err)
+	}
+	if err := d.deleteBoards(boardIDs); err != nil {
+		return err
+	}
+
+	result := d.tx.Where("id IN ?", projectIDs).Delete(&models.Project{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete projects: %w", result.Error)
+	}
+	d.summary.Projects += result.RowsAffected
+	d.resourceIDs = append(d.resourceIDs, projectIDs...)
+	return nil
+}
+
+func (d *cascadeDeleter) deleteBoards(boardIDs []string) error {
+	if len(boardIDs) == 0 {
+		return nil
+	}
+
+	var listIDs []string
+	if err := d.tx.Unscoped().Model(&models.List{}).Where("board_id IN ?", boardIDs).Pluck("id", &listIDs).Error; err != nil {
+		return fmt.Errorf("failed to find lists: %w", err)
+	}
+	if err := d.deleteLists(listIDs); err != nil {
+		return err
+	}
+
+	var labelIDs []string
+	if err := d.tx.Model(&models.Label{}).Where("board_id IN ?", boardIDs).Pluck("id", &labelIDs).Error; err != nil {
+		return fmt.Errorf("failed to find labels: %w", err)
+	}
+	if err := d.deleteLabels(labelIDs); err != nil {
+		return err
+	}
+
+	var webhookIDs []string
+	if err := d.tx.Model(&models.Webhook{}).Where("board_id IN ?", boardIDs).Pluck("id", &webhookIDs).Error; err != nil {
+		return fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	if err := d.deleteWebhooks(webhookIDs); err != nil {
+		return err
+	}
+
+	var automationIDs []string
+	if err := d.tx.Model(&models.Automation{}).Where("board_id IN ?", boardIDs).Pluck("id", &automationIDs).Error; err != nil {
+		return fmt.Errorf("failed to find automations: %w", err)
+	}
+	if err := d.deleteAutomations(automationIDs); err != nil {
+		return err
+	}
+
+	result := d.tx.Where("board_id IN ?", boardIDs).Delete(&models.Activity{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete activity: %w", result.Error)
+	}
+	d.summary.Activities += result.RowsAffected
+
+	result = d.tx.Unscoped().Where("id IN ?", boardIDs).Delete(&models.Board{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete boards: %w", result.Error)
+	}
+	d.summary.Boards += result.RowsAffected
+	d.resourceIDs = append(d.resourceIDs, boardIDs...)
+	return nil
+}
+
+func (d *cascadeDeleter) deleteLists(listIDs []string) error {
+	if len(listIDs) == 0 {
+		return nil
+	}
+
+	var cardIDs []string
+	if err := d.tx.Unscoped().Model(&models.Card{}).Where("list_id IN ?", listIDs).Pluck("id", &cardIDs).Error; err != nil {
+		return fmt.Errorf("failed to find cards: %w", err)
+	}
+	if err := d.deleteCards(cardIDs); err != nil {
+		return err
+	}
+
+	var ruleIDs []string
+	if err := d.tx.Model(&models.ListRule{}).Where("list_id IN ?", listIDs).Pluck("id", &ruleIDs).Error; err != nil {
+		return fmt.Errorf("failed to find list rules: %w", err)
+	}
+	if err := d.deleteListRules(ruleIDs); err != nil {
+		return err
+	}
+
+	result := d.tx.Unscoped().Where("id IN ?", listIDs).Delete(&models.List{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete lists: %w", result.Error)
+	}
+	d.summary.Lists += result.RowsAffected
+	d.resourceIDs = append(d.resourceIDs, listIDs...)
+	return nil
+}
+
+func (d *cascadeDeleter) deleteCards(cardIDs []string) error {
+	if len(cardIDs) == 0 {
+		return nil
+	}
+
+	result := d.tx.Exec("DELETE FROM card_labels WHERE card_id IN ?", cardIDs)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete card labels: %w", result.Error)
+	}
+	d.summary.CardLabels += result.RowsAffected
+
+	result = d.tx.Exec("DELETE FROM card_assignees WHERE card_id IN ?", cardIDs)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete card assignees: %w", result.Error)
+	}
+	d.summary.CardAssignees += result.RowsAffected
+
+	result = d.tx.Exec("DELETE FROM card_watchers WHERE card_id IN ?", cardIDs)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete card watchers: %w", result.Error)
+	}
+	d.summary.CardWatchers += result.RowsAffected
+
+	result = d.tx.Where("card_id IN ?", cardIDs).Delete(&models.Comment{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete comments: %w", result.Error)
+	}
+	d.summary.Comments += result.RowsAffected
+
+	checklists := d.tx.Model(&models.Checklist{}).Select("id").Where("card_id IN ?", cardIDs)
+	result = d.tx.Where("checklist_id IN (?)", checklists).Delete(&models.ChecklistItem{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete checklist items: %w", result.Error)
+	}
+	d.summary.ChecklistItems += result.RowsAffected
+
+	result = d.tx.Where("card_id IN ?", cardIDs).Delete(&models.Checklist{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete checklists: %w", result.Error)
+	}
+	d.summary.Checklists += result.RowsAffected
+
+	result = d.tx.Where("card_id IN ?", cardIDs).Delete(&models.Attachment{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete attachments: %w", result.Error)
+	}
+	d.summary.Attachments += result.RowsAffected
+
+	result = d.tx.Where("card_id IN ?", cardIDs).Delete(&models.CardListStay{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete list stays: %w", result.Error)
+	}
+	d.summary.ListStays += result.RowsAffected
+
+	result = d.tx.Unscoped().Where("id IN ?", cardIDs).Delete(&models.Card{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete cards: %w", result.Error)
+	}
+	d.summary.Cards += result.RowsAffected
+	d.resourceIDs = append(d.resourceIDs, cardIDs...)
+	return nil
+}
+
+func (d *cascadeDeleter) deleteLabels(labelIDs []string) error {
+	if len(labelIDs) == 0 {
+		return nil
+	}
+
+	result := d.tx.Exec("DELETE FROM card_labels WHERE label_id IN ?", labelIDs)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete card labels: %w", result.Error)
+	}
+	d.summary.CardLabels += result.RowsAffected
+
+	result = d.tx.Where("id IN ?", labelIDs).Delete(&models.Label{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete labels: %w", result.Error)
+	}
+	d.summary.Labels += result.RowsAffected
+	d.resourceIDs = append(d.resourceIDs, labelIDs...)
+	return nil
+}
+
+func (d *cascadeDeleter) deleteWebhooks(webhookIDs []string) error {
+	if len(webhookIDs) == 0 {
+		return nil
+	}
+
+	result := d.tx.Where("webhook_id IN ?", webhookIDs).Delete(&models.WebhookDelivery{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", result.Error)
+	}
+	d.summary.Deliveries += result.RowsAffected
+
+	result = d.tx.Where("id IN ?", webhookIDs).Delete(&models.Webhook{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhooks: %w", result.Error)
+	}
+	d.summary.Webhooks += result.RowsAffected
+	d.resourceIDs = append(d.resourceIDs, webhookIDs...)
+	return nil
+}
+
+func (d *cascadeDeleter) deleteListRules(ruleIDs []string) error {
+	if len(ruleIDs) == 0 {
+		return nil
+	}
+
+	result := d.tx.Where("rule_id IN ?", ruleIDs).Delete(&models.ListRuleRun{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete list rule runs: %w", result.Error)
+	}
+	d.summary.ListRuleRuns += result.RowsAffected
+
+	result = d.tx.Where("id IN ?", ruleIDs).Delete(&models.ListRule{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete list rules: %w", result.Error)
+	}
+	d.summary.ListRules += result.RowsAffected
+	return nil
+}
+
+func (d *cascadeDeleter) deleteAutomations(automationIDs []string) error {
+	if len(automationIDs) == 0 {
+		return nil
+	}
+
+	result := d.tx.Where("automation_id IN ?", automationIDs).Delete(&models.AutomationExecution{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete automation executions: %w", result.Error)
+	}
+	d.summary.AutomationExecutions += result.RowsAffected
+
+	result = d.tx.Where("id IN ?", automationIDs).Delete(&models.Automation{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete automations: %w", result.Error)
+	}
+	d.summary.Automations += result.RowsAffected
+	return nil
+}
+
+// removePolicies drops the Casbin policies and parent links of every deleted
+// resource. It must only be called after the transaction has committed.
+func (d *cascadeDeleter) removePolicies() error {
+	authService := auth.NewAuthorizationService()
+	for _, id := range d.resourceIDs {
+		removed, err := authService.RemoveResourcePolicies(id)
+		d.summary.Policies += int64(removed)
+		if err != nil {
+			return fmt.Errorf("failed to remove policies for %s: %w", id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ChecklistService struct {
+	hierarchyService *HierarchyService
+	memberService    *OrganizationMemberService
+}
+
+func NewChecklistService() *ChecklistService {
+	return &ChecklistService{
+		hierarchyService: NewHierarchyService(),
+		memberService:    NewOrganizationMemberService(),
+	}
+}
+
+func (s *ChecklistService) GetChecklists(cardID string) ([]models.Checklist, error) {
+	var checklists []models.Checklist
+	result := database.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("checklist_items.position ASC")
+	}).Preload("Items.Assignee").Where("card_id = ?", cardID).Order("position ASC").Find(&checklists)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve checklists: %w", result.Error)
+	}
+	return checklists, nil
+}
+
+func (s *ChecklistService) GetChecklistByID(checklistID string) (*models.Checklist, error) {
+	var checklist models.Checklist
+	result := database.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("checklist_items.position ASC")
+	}).Preload("Items.Assignee").First(&checklist, "id = ?", checklistID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("checklist not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve checklist: %w", result.Error)
+	}
+	return &checklist, nil
+}
+
+func (s *ChecklistService) CreateChecklist(cardID, title string) (*models.Checklist, error) {
+	checklist := models.Checklist{
+		ID:        uuid.New().String(),
+		CardID:    cardID,
+		Title:     title,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var maxPosition int
+		if err := tx.Model(&models.Checklist{}).Select("COALESCE(MAX(position), 0)").Where("card_id = ?", cardID).Row().Scan(&maxPosition); err != nil {
+			return fmt.Errorf("failed to get max checklist position: %w", err)
+		}
+		checklist.Position = maxPosition + 1
+		return tx.Create(&checklist).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checklist: %w", err)
+	}
+
+	log.Printf("Checklist created: %s on card %s\n", checklist.Title, cardID)
+	checklist.Items = []*models.ChecklistItem{}
+	return &checklist, nil
+}
+
+func (s *ChecklistService) UpdateChecklist(checklistID string, version int, updateReq models.UpdateChecklistRequest) (*models.Checklist, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var checklist models.Checklist
+		if err := tx.First(&checklist, "id = ?", checklistID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("checklist not found")
+			}
+			return err
+		}
+		if err := claimVersion(tx, &checklist, &checklist.Version, version); err != nil {
+			return err
+		}
+
+		columns := map[string]any{}
+		if updateReq.Position != nil {
+			var count int64
+			if err := tx.Model(&models.Checklist{}).Where("card_id = ?", checklist.CardID).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to count checklists: %w", err)
+			}
+			newPosition := min(*updateReq.Position, int(count))
+			if err := shiftPositions(tx, &models.Checklist{}, "card_id", checklist.CardID, checklist.Position, newPosition); err != nil {
+				return err
+			}
+			checklist.Position = newPosition
+			columns["position"] = checklist.Position
+		}
+		if updateReq.Title != "" {
+			checklist.Title = updateReq.Title
+			columns["title"] = checklist.Title
+		}
+		checklist.UpdatedAt = time.Now()
+		columns["updated_at"] = checklist.UpdatedAt
+		return tx.Model(&checklist).UpdateColumns(columns).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "checklist not found") || errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update checklist: %w", err)
+	}
+
+	return s.GetChecklistByID(checklistID)
+}
+
+func (s *ChecklistService) DeleteChecklist(checklistID string, version int) error {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var checklist models.Checklist
+		if err := tx.First(&checklist, "id = ?", checklistID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("checklist not found or already deleted")
+			}
+			return err
+		}
+		if err := claimVersion(tx, &checklist, &checklist.Version, version); err != nil {
+			return err
+		}
+		if err := tx.Where("checklist_id = ?", checklistID).Delete(&models.ChecklistItem{}).Error; err != nil {
+			return fmt.Errorf("failed to delete checklist items: %w", err)
+		}
+		if err := tx.Delete(&checklist).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Checklist{}).Where("card_id = ? AND position > ?", checklist.CardID, checklist.Position).Update("position", gorm.Expr("position - 1")).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "not found or already deleted") || errors.Is(err, errVersionMismatch) {
+			return err
+		}
+		return fmt.Errorf("failed to delete checklist: %w", err)
+	}
+
+	log.Printf("Checklist deleted: ID %s\n", checklistID)
+	return nil
+}
+
+func (s *ChecklistService) CreateItem(checklistID string, req models.CreateChecklistItemRequest) (*models.ChecklistItem, error) {
+	if req.AssigneeID != nil {
+		if err := s.checkAssignee(checklistID, *req.AssigneeID); err != nil {
+			return nil, err
+		}
+	}
+
+	item := models.ChecklistItem{
+		ID:          uuid.New().String(),
+		ChecklistID: checklistID,
+		Content:     req.Content,
+		AssigneeID:  req.AssigneeID,
+		DueDate:     req.DueDate,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var maxPosition int
+		if err := tx.Model(&models.ChecklistItem{}).Select("COALESCE(MAX(position), 0)").Where("checklist_id = ?", checklistID).Row().Scan(&maxPosition); err != nil {
+			return fmt.Errorf("failed to get max item position: %w", err)
+		}
+		item.Position = maxPosition + 1
+		return tx.Create(&item).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checklist item: %w", err)
+	}
+
+	return s.getItemByID(item.ID)
+}
+
+func (s *ChecklistService) UpdateItem(itemID string, version int, updateReq models.UpdateChecklistItemRequest) (*models.ChecklistItem, error) {
+	item, err := s.getItemByID(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateReq.AssigneeID.Set && !updateReq.AssigneeID.Null {
+		if err := s.checkAssignee(item.ChecklistID, updateReq.AssigneeID.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, item, &item.Version, version); err != nil {
+			return err
+		}
+		columns := map[string]any{}
+		if updateReq.Position != nil {
+			var count int64
+			if err := tx.Model(&models.ChecklistItem{}).Where("checklist_id = ?", item.ChecklistID).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to count items: %w", err)
+			}
+			newPosition := min(*updateReq.Position, int(count))
+			if err := shiftPositions(tx, &models.ChecklistItem{}, "checklist_id", item.ChecklistID, item.Position, newPosition); err != nil {
+				return err
+			}
+			item.Position = newPosition
+			columns["position"] = item.Position
+		}
+
+		if updateReq.Content != "" {
+			item.Content = updateReq.Content
+			columns["content"] = item.Content
+		}
+		if updateReq.Completed != nil && *updateReq.Completed != item.Completed {
+			item.Completed = *updateReq.Completed
+			item.CompletedAt = nil
+			if item.Completed {
+				now := time.Now()
+				item.CompletedAt = &now
+			}
+			columns["completed"] = item.Completed
+			columns["completed_at"] = item.CompletedAt
+		}
+		if updateReq.AssigneeID.Set {
+			item.AssigneeID = updateReq.AssigneeID.Ptr()
+			columns["assignee_id"] = item.AssigneeID
+		}
+		if updateReq.DueDate.Set {
+			item.DueDate = updateReq.DueDate.Ptr()
+			columns["due_date"] = item.DueDate
+		}
+		item.UpdatedAt = time.Now()
+		columns["updated_at"] = item.UpdatedAt
+		return tx.Model(item).UpdateColumns(columns).Error
+	})
+	if err != nil {
+		if errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update checklist item: %w", err)
+	}
+
+	return s.getItemByID(itemID)
+}
+
+func (s *ChecklistService) DeleteItem(itemID string, version int) error {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var item models.ChecklistItem
+		if err := tx.First(&item, "id = ?", itemID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("checklist item not found or already deleted")
+			}
+			return err
+		}
+		if err := claimVersion(tx, &item, &item.Version, version); err != nil {
+			return err
+		}
+		if err := tx.Delete(&item).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.ChecklistItem{}).Where("checklist_id = ? AND position > ?", item.ChecklistID, item.Position).Update("position", gorm.Expr("position - 1")).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "not found or already deleted") || errors.Is(err, errVersionMismatch) {
+			return err
+		}
+		return fmt.Errorf("failed to delete checklist item: %w", err)
+	}
+	return nil
+}
+
+// AttachProgress fills in the checklist progress of every card that has at
+// least one checklist item.
+func (s *ChecklistService) AttachProgress(cards []*models.Card) error {
+	if len(cards) == 0 {
+		return nil
+	}
+
+	cardIDs := make([]string, 0, len(cards))
+	for _, card := range cards {
+		cardIDs = append(cardIDs, card.ID)
+	}
+
+	var rows []struct {
+		CardID    string
+		Completed int64
+		Total     int64
+	}
+	err := database.DB.Model(&models.ChecklistItem{}).
+		Select("checklists.card_id, SUM(CASE WHEN checklist_items.completed THEN 1 ELSE 0 END) AS completed, COUNT(*) AS total").
+		Joins("JOIN checklists ON checklists.id = checklist_items.checklist_id").
+		Where("checklists.card_id IN ?", cardIDs).
+		Group("checklists.card_id").
+		Scan(&rows).Error
+	if err != nil {
+		return fmt.Errorf("failed to compute checklist progress: %w", err)
+	}
+
+	progress := make(map[string]*models.ChecklistProgress, len(rows))
+	for _, row := range rows {
+		progress[row.CardID] = &models.ChecklistProgress{Completed: row.Completed, Total: row.Total}
+	}
+	for _, card := range cards {
+		card.ChecklistProgress = progress[card.ID]
+	}
+	return nil
+}
+
+func (s *ChecklistService) getItemByID(itemID string) (*models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	result := database.DB.Preload("Assignee").First(&item, "id = ?", itemID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("checklist item not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve checklist item: %w", result.Error)
+	}
+	return &item, nil
+}
+
+// checkAssignee ensures an item is only assigned to a member of the
+// organization owning the checklist's card.
+func (s *ChecklistService) checkAssignee(checklistID, userID string) error {
+	path, err := s.hierarchyService.ResolvePath(models.ResourcePath{ChecklistID: checklistID})
+	if err != nil {
+		return err
+	}
+	_, err = s.memberService.GetAcceptedMember(path.OrganizationID, userID)
+	return err
+}
+
+// shiftPositions makes room for a row moving from oldPosition to newPosition
+// among the rows sharing its parent, as MoveList does for lists.
+func shiftPositions(tx *gorm.DB, model any, parentColumn, parentID string, oldPosition, newPosition int) error {
+	switch {
+	case oldPosition < newPosition:
+		return tx.Model(model).Where(parentColumn+" = ? AND position > ? AND position <= ?", parentID, oldPosition, newPosition).Update("position", gorm.Expr("position - 1")).Error
+	case oldPosition > newPosition:
+		return tx.Model(model).Where(parentColumn+" = ? AND position >= ? AND position < ?", parentID, newPosition, oldPosition).Update("position", gorm.Expr("position + 1")).Error
+	}
+	return nil
+}
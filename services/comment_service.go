@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type CommentService struct{}
@@ -25,16 +26,61 @@ func (s *CommentService) CreateComment(cardID, userID, content string) (*models.
 		UpdatedAt: time.Now(),
 	}
 
-	if err := database.DB.Create(&comment).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var card models.Card
+		if err := tx.First(&card, "id = ?", cardID).Error; err != nil {
+			return err
+		}
+		return s.addComment(tx, &card, &comment)
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to create comment: %w", err)
 	}
 
+	publishCardEvent(cardID, models.EventCommentAdded, userID, comment)
 	return &comment, nil
 }
 
-func (s *CommentService) DeleteComment(commentID string) error {
-	if err := database.DB.Delete(&models.Comment{}, "id = ?", commentID).Error; err != nil {
-		return fmt.Errorf("failed to delete comment: %w", err)
+// addComment inserts the comment on the card inside tx, loading its author
+// and recording it in the card's history.
+func (s *CommentService) addComment(tx *gorm.DB, card *models.Card, comment *models.Comment) error {
+	if err := tx.Create(comment).Error; err != nil {
+		return err
+	}
+	if err := tx.First(&comment.User, "id = ?", comment.UserID).Error; err != nil {
+		return err
+	}
+	return recordActivity(tx, comment.UserID, card, models.ActivityCommentAdded, nil, map[string]any{"comment_id": comment.ID, "content": comment.Content})
+}
+
+// DeleteComment deletes the comment if it is still at the given version. A
+// comment that is already gone is left alone.
+func (s *CommentService) DeleteComment(commentID string, version int, actorID string) error {
+	var comment models.Comment
+	var deleted bool
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Limit(1).Find(&comment, "id = ?", commentID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to find comment: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := claimVersion(tx, &comment, &comment.Version, version); err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Comment{}, "id = ?", commentID).Error; err != nil {
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+		deleted = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if deleted {
+		publishCardEvent(comment.CardID, models.EventCommentDeleted, actorID, models.CommentDeletedEvent{CommentID: commentID, CardID: comment.CardID})
 	}
 	return nil
 }
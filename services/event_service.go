@@ -0,0 +1,150 @@
+package services
+
+import (
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before it
+// is disconnected.
+const subscriberBuffer = 64
+
+// eventHub fans board events out to the subscribers of each board. A
+// subscriber that falls too far behind is disconnected rather than silently
+// missing events, so it can reconnect and reload the board. Listeners receive
+// the events of every board, synchronously, and must not block for long.
+type eventHub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan models.BoardEvent]struct{}
+	listeners   []func(models.BoardEvent)
+}
+
+var boardEvents = &eventHub{subscribers: map[string]map[chan models.BoardEvent]struct{}{}}
+
+func (h *eventHub) subscribe(boardID string) (<-chan models.BoardEvent, func()) {
+	ch := make(chan models.BoardEvent, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[boardID] == nil {
+		h.subscribers[boardID] = map[chan models.BoardEvent]struct{}{}
+	}
+	h.subscribers[boardID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(boardID, ch)
+	}
+}
+
+func (h *eventHub) listen(listener func(models.BoardEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.listeners = append(h.listeners, listener)
+}
+
+func (h *eventHub) publish(event models.BoardEvent) {
+	h.mu.Lock()
+	for ch := range h.subscribers[event.BoardID] {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("Disconnecting slow subscriber of board %s\n", event.BoardID)
+			h.remove(event.BoardID, ch)
+		}
+	}
+	listeners := h.listeners
+	h.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// remove must be called with the lock held. Closing the channel tells the
+// subscriber it has been disconnected.
+func (h *eventHub) remove(boardID string, ch chan models.BoardEvent) {
+	if _, ok := h.subscribers[boardID][ch]; !ok {
+		return
+	}
+	delete(h.subscribers[boardID], ch)
+	close(ch)
+	if len(h.subscribers[boardID]) == 0 {
+		delete(h.subscribers, boardID)
+	}
+}
+
+type EventService struct{}
+
+func NewEventService() *EventService {
+	return &EventService{}
+}
+
+// Subscribe returns the events of a board as they are published and a
+// function that must be called once the subscriber stops listening. The
+// channel is closed if the subscriber falls too far behind.
+func (s *EventService) Subscribe(boardID string) (<-chan models.BoardEvent, func()) {
+	return boardEvents.subscribe(boardID)
+}
+
+// publishBoardEvent announces a change to a board. It must only be called
+// once the change has been committed.
+func publishBoardEvent(boardID, eventType, actorID string, data any) {
+	boardEvents.publish(models.BoardEvent{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		BoardID:   boardID,
+		ActorID:   actorID,
+		Data:      data,
+		CreatedAt: time.Now(),
+	})
+}
+
+// publishListEvent announces a change to something inside a list, such as
+// one of its cards, on the list's board.
+func publishListEvent(listID, eventType, actorID string, data any) {
+	var boardID string
+	if err := database.DB.Unscoped().Model(&models.List{}).Select("board_id").Where("id = ?", listID).Row().Scan(&boardID); err != nil {
+		log.Printf("Failed to publish %s: board of list %s not found: %v\n", eventType, listID, err)
+		return
+	}
+	publishBoardEvent(boardID, eventType, actorID, data)
+}
+
+// publishCardEvent announces a change to something attached to a card, such
+// as a comment, on the card's board.
+func publishCardEvent(cardID, eventType, actorID string, data any) {
+	var listID string
+	if err := database.DB.Unscoped().Model(&models.Card{}).Select("list_id").Where("id = ?", cardID).Row().Scan(&listID); err != nil {
+		log.Printf("Failed to publish %s: list of card %s not found: %v\n", eventType, cardID, err)
+		return
+	}
+	publishListEvent(listID, eventType, actorID, data)
+}
+
+// publishLabelEvent announces a change to a label on its board, or on every
+// board of its organization for an organization label.
+func publishLabelEvent(label *models.Label, eventType, actorID string, data any) {
+	var boardIDs []string
+	if label.BoardID != "" {
+		boardIDs = []string{label.BoardID}
+	} else {
+		err := database.DB.Model(&models.Board{}).
+			Joins("JOIN projects ON projects.id = boards.project_id").
+			Where("projects.organization_id = ?", label.OrganizationID).
+			Pluck("boards.id", &boardIDs).Error
+		if err != nil {
+			log.Printf("Failed to publish %s: boards of organization %s not found: %v\n", eventType, label.OrganizationID, err)
+			return
+		}
+	}
+	for _, boardID := range boardIDs {
+		publishBoardEvent(boardID, eventType, actorID, data)
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"fmt"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+
+	"gorm.io/gorm"
+)
+
+type HierarchyService struct{}
+
+func NewHierarchyService() *HierarchyService {
+	return &HierarchyService{}
+}
+
+// ResolvePath loads the parents of every resource named in ids, from the
+// deepest one up to its organization, and checks that each ID supplied by the
+// caller lies on that chain. The returned path is fully populated.
+func (s *HierarchyService) ResolvePath(ids models.ResourcePath) (*models.ResourcePath, error) {
+	return s.resolvePath(database.DB, ids)
+}
+
+// resolvePath is ResolvePath reading through db, so a transaction sees the
+// changes it has made so far.
+func (s *HierarchyService) resolvePath(db *gorm.DB, ids models.ResourcePath) (*models.ResourcePath, error) {
+	path := ids
+
+	levels := []struct {
+		name         string
+		model        any
+		id           *string
+		parentColumn string
+		parentName   string
+		parentID     *string
+	}{
+		{"checklist item", &models.ChecklistItem{}, &path.ItemID, "checklist_id", "checklist", &path.ChecklistID},
+		{"checklist", &models.Checklist{}, &path.ChecklistID, "card_id", "card", &path.CardID},
+		{"comment", &models.Comment{}, &path.CommentID, "card_id", "card", &path.CardID},
+		{"attachment", &models.Attachment{}, &path.AttachmentID, "card_id", "card", &path.CardID},
+		{"card", &models.Card{}, &path.CardID, "list_id", "list", &path.ListID},
+		{"list", &models.List{}, &path.ListID, "board_id", "board", &path.BoardID},
+		{"board", &models.Board{}, &path.BoardID, "project_id", "project", &path.ProjectID},
+		{"project", &models.Project{}, &path.ProjectID, "organization_id", "organization", &path.OrganizationID},
+	}
+
+	for _, level := range levels {
+		if *level.id == "" {
+			continue
+		}
+
+		var parents []string
+		if err := db.Model(level.model).Where("id = ?", *level.id).Pluck(level.parentColumn, &parents).Error; err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", level.name, err)
+		}
+		if len(parents) == 0 {
+			return nil, fmt.Errorf("%s not found", level.name)
+		}
+		if *level.parentID != "" && *level.parentID != parents[0] {
+			return nil, fmt.Errorf("%s not found in this %s", level.name, level.parentName)
+		}
+		*level.parentID = parents[0]
+	}
+
+	if path.OrganizationID != "" {
+		var count int64
+		if err := db.Model(&models.Organization{}).Where("id = ?", path.OrganizationID).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to resolve organization: %w", err)
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("organization not found")
+		}
+	}
+
+	return &path, nil
+}
@@ -3,8 +3,10 @@ package services
 import (
 	"errors"
 	"fmt"
+	"kanban-app/api/auth"
 	"kanban-app/api/database"
 	"kanban-app/api/models"
+	"log"
 	"strings"
 	"time"
 
@@ -18,13 +20,17 @@ func NewLabelService() *LabelService {
 	return &LabelService{}
 }
 
-func (s *LabelService) CreateLabel(name, color string) (*models.Label, error) {
+// CreateLabel creates a label for the whole organization, or for a single
+// board of it when boardID is set.
+func (s *LabelService) CreateLabel(orgID, boardID, name, color, actorID string) (*models.Label, error) {
 	label := models.Label{
-		ID:        uuid.New().String(),
-		Name:      name,
-		Color:     color,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:             uuid.New().String(),
+		OrganizationID: orgID,
+		BoardID:        boardID,
+		Name:           name,
+		Color:          color,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
 	}
 
 	result := database.DB.Create(&label)
@@ -35,12 +41,29 @@ func (s *LabelService) CreateLabel(name, color string) (*models.Label, error) {
 		return nil, fmt.Errorf("failed to create label: %w", result.Error)
 	}
 
+	if _, err := auth.NewAuthorizationService().AddResourceParent(label.ID, label.ScopeID()); err != nil {
+		return nil, fmt.Errorf("failed to link new label to its scope: %w", err)
+	}
+
+	log.Printf("Label created: %s in scope %s\n", label.Name, label.ScopeID())
+	publishLabelEvent(&label, models.EventLabelCreated, actorID, label)
 	return &label, nil
 }
 
-func (s *LabelService) GetAllLabels() ([]models.Label, error) {
+func (s *LabelService) GetOrganizationLabels(orgID string) ([]models.Label, error) {
+	var labels []models.Label
+	result := database.DB.Where("organization_id = ? AND board_id = ''", orgID).Order("name ASC").Find(&labels)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve labels: %w", result.Error)
+	}
+	return labels, nil
+}
+
+// GetBoardLabels returns the labels usable on a board: its own labels and
+// those of its organization.
+func (s *LabelService) GetBoardLabels(orgID, boardID string) ([]models.Label, error) {
 	var labels []models.Label
-	result := database.DB.Find(&labels)
+	result := database.DB.Where("organization_id = ? AND board_id IN ?", orgID, []string{"", boardID}).Order("name ASC").Find(&labels)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to retrieve labels: %w", result.Error)
 	}
@@ -59,37 +82,84 @@ func (s *LabelService) GetLabelByID(labelID string) (*models.Label, error) {
 	return &label, nil
 }
 
-func (s *LabelService) UpdateLabel(labelID string, updateReq models.UpdateLabelRequest) (*models.Label, error) {
+func (s *LabelService) UpdateLabel(labelID string, version int, actorID string, updateReq models.UpdateLabelRequest) (*models.Label, error) {
 	label, err := s.GetLabelByID(labelID)
 	if err != nil {
 		return nil, err
 	}
 
+	columns := map[string]any{}
 	if updateReq.Name != nil {
 		label.Name = *updateReq.Name
+		columns["name"] = label.Name
 	}
 	if updateReq.Color != nil {
 		label.Color = *updateReq.Color
+		columns["color"] = label.Color
 	}
 	label.UpdatedAt = time.Now()
+	columns["updated_at"] = label.UpdatedAt
 
-	result := database.DB.Save(&label)
-	if result.Error != nil {
-		if strings.Contains(result.Error.Error(), "UNIQUE constraint failed") && strings.Contains(result.Error.Error(), "labels.name") {
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, label, &label.Version, version); err != nil {
+			return err
+		}
+		return tx.Model(label).UpdateColumns(columns).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") && strings.Contains(err.Error(), "labels.name") {
 			return nil, errors.New("label name already exists")
 		}
-		return nil, fmt.Errorf("failed to update label: %w", result.Error)
+		if errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update label: %w", err)
 	}
+
+	publishLabelEvent(label, models.EventLabelUpdated, actorID, label)
 	return label, nil
 }
 
-func (s *LabelService) DeleteLabel(labelID string) error {
-	result := database.DB.Delete(&models.Label{}, "id = ?", labelID)
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete label: %w", result.Error)
+// DeleteLabel removes the label from every card carrying it before deleting it.
+func (s *LabelService) DeleteLabel(labelID string, version int, actorID string) error {
+	var deleter *cascadeDeleter
+	var label models.Label
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&label, "id = ?", labelID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("label not found or already deleted")
+			}
+			return err
+		}
+		if err := claimVersion(tx, &label, &label.Version, version); err != nil {
+			return err
+		}
+		deleter = newCascadeDeleter(tx)
+		if err := deleter.deleteLabels([]string{labelID}); err != nil {
+			return err
+		}
+		if deleter.summary.Labels == 0 {
+			return errors.New("label not found or already deleted")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := deleter.removePolicies(); err != nil {
+		return err
 	}
-	if result.RowsAffected == 0 {
-		return errors.New("label not found or already deleted")
+
+	log.Printf("Label deleted: ID %s\n", labelID)
+	publishLabelEvent(&label, models.EventLabelDeleted, actorID, models.LabelDeletedEvent{LabelID: labelID})
+	return nil
+}
+
+// checkLabelScope reports an error unless the label can be used on the board.
+func (s *LabelService) checkLabelScope(label *models.Label, orgID, boardID string) error {
+	if label.OrganizationID != orgID || (label.BoardID != "" && label.BoardID != boardID) {
+		return fmt.Errorf("label %s is not available on this board", label.ID)
 	}
 	return nil
 }
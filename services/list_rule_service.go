@@ -0,0 +1,258 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// listRuleRunRetention is how long the log of rule runs is kept.
+const listRuleRunRetention = 30 * 24 * time.Hour
+
+type ListRuleService struct {
+	cardService *CardService
+}
+
+func NewListRuleService() *ListRuleService {
+	return &ListRuleService{cardService: NewCardService()}
+}
+
+func (s *ListRuleService) CreateRule(listID, actorID string, req models.CreateListRuleRequest) (*models.ListRule, error) {
+	rule := models.ListRule{
+		ID:        uuid.New().String(),
+		ListID:    listID,
+		Type:      req.Type,
+		Days:      req.Days,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+		CreatedBy: actorID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := database.DB.Create(&rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to create rule: %w", err)
+	}
+
+	log.Printf("List rule created: %s (%s after %d days) on list %s by user %s\n", rule.ID, rule.Type, rule.Days, listID, actorID)
+	return &rule, nil
+}
+
+func (s *ListRuleService) GetRules(listID string) ([]models.ListRule, error) {
+	var rules []models.ListRule
+	result := database.DB.Where("list_id = ?", listID).Order("created_at ASC").Find(&rules)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve rules: %w", result.Error)
+	}
+	return rules, nil
+}
+
+func (s *ListRuleService) GetRuleByID(listID, ruleID string) (*models.ListRule, error) {
+	var rule models.ListRule
+	result := database.DB.First(&rule, "id = ? AND list_id = ?", ruleID, listID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("rule not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve rule: %w", result.Error)
+	}
+	return &rule, nil
+}
+
+func (s *ListRuleService) UpdateRule(listID, ruleID string, version int, updateReq models.UpdateListRuleRequest) (*models.ListRule, error) {
+	rule, err := s.GetRuleByID(listID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]any{}
+	if updateReq.Days != nil {
+		rule.Days = *updateReq.Days
+		columns["days"] = rule.Days
+	}
+	if updateReq.Enabled != nil {
+		rule.Enabled = *updateReq.Enabled
+		columns["enabled"] = rule.Enabled
+	}
+	rule.UpdatedAt = time.Now()
+	columns["updated_at"] = rule.UpdatedAt
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, rule, &rule.Version, version); err != nil {
+			return err
+		}
+		return tx.Model(rule).UpdateColumns(columns).Error
+	})
+	if err != nil {
+		if errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update rule: %w", err)
+	}
+	return rule, nil
+}
+
+// DeleteRule removes the rule together with its run log.
+func (s *ListRuleService) DeleteRule(listID, ruleID string, version int) error {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var rule models.ListRule
+		if err := tx.First(&rule, "id = ? AND list_id = ?", ruleID, listID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("rule not found or already deleted")
+			}
+			return err
+		}
+		if err := claimVersion(tx, &rule, &rule.Version, version); err != nil {
+			return err
+		}
+		return newCascadeDeleter(tx).deleteListRules([]string{ruleID})
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("List rule deleted: ID %s\n", ruleID)
+	return nil
+}
+
+// GetRuns returns a page of a rule's run log, newest first.
+func (s *ListRuleService) GetRuns(listID, ruleID string, query models.PaginationQuery) (*models.ListRuleRunPage, error) {
+	if _, err := s.GetRuleByID(listID, ruleID); err != nil {
+		return nil, err
+	}
+
+	query = query.Normalize()
+	page := models.ListRuleRunPage{Runs: []models.ListRuleRun{}, Page: query.Page, PerPage: query.PerPage}
+
+	if err := database.DB.Model(&models.ListRuleRun{}).Where("rule_id = ?", ruleID).Count(&page.Total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count rule runs: %w", err)
+	}
+
+	result := database.DB.Where("rule_id = ?", ruleID).
+		Order("started_at DESC, id DESC").
+		Limit(query.PerPage).Offset(query.Offset()).
+		Find(&page.Runs)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve rule runs: %w", result.Error)
+	}
+	return &page, nil
+}
+
+// RunAll evaluates every enabled rule on a list that is neither in the trash
+// nor archived, and returns how many rules ran. A rule that fails is logged
+// in its run log and doesn't stop the others.
+func (s *ListRuleService) RunAll() (int, error) {
+	var rules []models.ListRule
+	result := database.DB.Joins("JOIN lists ON lists.id = list_rules.list_id AND lists.deleted_at IS NULL AND lists.archived_at IS NULL").
+		Where("list_rules.enabled = ?", true).
+		Order("list_rules.created_at ASC").
+		Find(&rules)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to find rules: %w", result.Error)
+	}
+
+	for i := range rules {
+		if err := s.run(&rules[i]); err != nil {
+			return i, err
+		}
+	}
+
+	cutoff := time.Now().Add(-listRuleRunRetention)
+	if err := database.DB.Where("started_at < ?", cutoff).Delete(&models.ListRuleRun{}).Error; err != nil {
+		return len(rules), fmt.Errorf("failed to prune rule runs: %w", err)
+	}
+	return len(rules), nil
+}
+
+// run evaluates one rule in a transaction of its own and logs the run. The
+// changes are attributed to the user who created the rule.
+func (s *ListRuleService) run(rule *models.ListRule) error {
+	run := models.ListRuleRun{
+		ID:        uuid.New().String(),
+		RuleID:    rule.ID,
+		ListID:    rule.ListID,
+		StartedAt: time.Now(),
+		CardIDs:   []string{},
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		switch rule.Type {
+		case models.ListRuleArchiveAfter:
+			cardIDs, err := s.archiveStaleCards(tx, rule, run.StartedAt)
+			if err != nil {
+				return err
+			}
+			run.CardIDs = cardIDs
+		default:
+			return fmt.Errorf("unknown rule type %s", rule.Type)
+		}
+		return tx.Model(rule).UpdateColumn("last_run_at", run.StartedAt).Error
+	})
+	if err != nil {
+		run.CardIDs = []string{}
+		run.Error = err.Error()
+	}
+	run.Archived = len(run.CardIDs)
+	run.FinishedAt = time.Now()
+
+	if err := database.DB.Create(&run).Error; err != nil {
+		return fmt.Errorf("failed to log rule run: %w", err)
+	}
+	if run.Error != "" {
+		log.Printf("List rule %s on list %s failed: %s\n", rule.ID, rule.ListID, run.Error)
+		return nil
+	}
+	log.Printf("List rule %s on list %s ran: %d cards archived\n", rule.ID, rule.ListID, run.Archived)
+
+	for _, cardID := range run.CardIDs {
+		if _, err := s.cardService.reloadAndPublish(cardID, models.EventCardArchived, rule.CreatedBy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveStaleCards archives the cards that entered the rule's list more
+// than the rule's number of days before now. Cards without an entry time
+// count from their last update.
+func (s *ListRuleService) archiveStaleCards(tx *gorm.DB, rule *models.ListRule, now time.Time) ([]string, error) {
+	cutoff := now.AddDate(0, 0, -rule.Days)
+
+	var cards []models.Card
+	result := tx.Where("list_id = ? AND archived_at IS NULL AND COALESCE(list_entered_at, updated_at) < ?", rule.ListID, cutoff).
+		Order(rankOrder).
+		Find(&cards)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find cards to archive: %w", result.Error)
+	}
+
+	cardIDs := make([]string, 0, len(cards))
+	for i := range cards {
+		if err := claimVersion(tx, &cards[i], &cards[i].Version, AnyVersion); err != nil {
+			return nil, err
+		}
+		if err := s.cardService.archiveCard(tx, &cards[i], rule.CreatedBy); err != nil {
+			return nil, err
+		}
+		cardIDs = append(cardIDs, cards[i].ID)
+	}
+	return cardIDs, nil
+}
+
+// StartScheduler runs RunAll in the background every interval.
+func (s *ListRuleService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if _, err := s.RunAll(); err != nil {
+				log.Printf("List rules failed: %v\n", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -7,6 +7,7 @@ import (
 	"kanban-app/api/database"
 	"kanban-app/api/models"
 	"log"
+	"math"
 	"strings"
 	"time"
 
@@ -20,43 +21,56 @@ func NewListService() *ListService {
 	return &ListService{}
 }
 
-func (s *ListService) CreateList(boardID, name, userID string) (*models.List, error) {
-	var maxPosition int
-	result := database.DB.Table("lists").Select("COALESCE(MAX(position), 0)").Where("board_id = ?", boardID).Row().Scan(&maxPosition)
-	if result != nil && !errors.Is(result, gorm.ErrRecordNotFound) {
-		return nil, fmt.Errorf("failed to get max list position: %w", result)
-	}
-
+func (s *ListService) CreateList(boardID, name string, wipLimit *int, flowState, userID string) (*models.List, error) {
 	newList := models.List{
 		ID:        uuid.New().String(),
 		BoardID:   boardID,
 		Name:      name,
-		Position:  maxPosition + 1,
+		WIPLimit:  wipLimit,
+		FlowState: flowState,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	createResult := database.DB.Create(&newList)
-	if createResult.Error != nil {
-		if strings.Contains(createResult.Error.Error(), "UNIQUE constraint failed") {
+	// Rank the list after the last one in the same transaction that inserts
+	// it, so concurrent creates cannot both take the same place
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		rank, err := rankAt(tx, &models.List{}, "board_id", boardID, "", "", math.MaxInt)
+		if err != nil {
+			return err
+		}
+		newList.Rank = rank
+		return tx.Create(&newList).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
 			return nil, errors.New("list name already exists within this board")
 		}
-		return nil, fmt.Errorf("failed to create list: %w", createResult.Error)
+		return nil, fmt.Errorf("failed to create list: %w", err)
 	}
 
-	log.Printf("List created: %s in board %s at position %d\n", newList.Name, newList.BoardID, newList.Position)
+	log.Printf("List created: %s in board %s at rank %s by user %s\n", newList.Name, newList.BoardID, newList.Rank, userID)
 
-	_, err := auth.NewAuthorizationService().AddPolicy(userID, newList.ID, "owner")
+	_, err = auth.NewAuthorizationService().AddResourceParent(newList.ID, boardID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add policy for new list: %w", err)
+		return nil, fmt.Errorf("failed to link new list to board: %w", err)
 	}
 
+	newList.Cards = []*models.Card{}
+	publishBoardEvent(boardID, models.EventListCreated, userID, newList)
 	return &newList, nil
 }
 
-func (s *ListService) GetListsByBoardID(boardID string) ([]models.List, error) {
+// GetListsByBoardID returns the board's lists in rank order, leaving out
+// archived lists unless includeArchived is set.
+func (s *ListService) GetListsByBoardID(boardID string, includeArchived bool) ([]models.List, error) {
+	query := database.DB.Where("board_id = ?", boardID)
+	if !includeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
+
 	var lists []models.List
-	result := database.DB.Where("board_id = ?", boardID).Order("position ASC").Find(&lists)
+	result := query.Order(rankOrder).Find(&lists)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to retrieve lists: %w", result.Error)
 	}
@@ -75,105 +89,207 @@ func (s *ListService) GetListByID(listID string) (*models.List, error) {
 	return &list, nil
 }
 
-func (s *ListService) UpdateList(listID string, updateReq models.UpdateListRequest) (*models.List, error) {
+func (s *ListService) UpdateList(listID string, version int, actorID string, updateReq models.UpdateListRequest) (*models.List, error) {
+	if updateReq.Position != nil && *updateReq.Position < 1 {
+		return nil, errors.New("invalid position: must be greater than 0")
+	}
+
 	list, err := s.GetListByID(listID)
 	if err != nil {
 		return nil, err
 	}
 
-	if updateReq.Name != "" {
-		list.Name = updateReq.Name
-		list.UpdatedAt = time.Now()
-		if err := database.DB.Save(&list).Error; err != nil {
-			if strings.Contains(err.Error(), "UNIQUE constraint failed") {
-				return nil, errors.New("list name already exists within this board")
+	changed := updateReq.Name != "" || updateReq.WIPLimit.Set || updateReq.FlowState.Set
+	moved := false
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, list, &list.Version, version); err != nil {
+			return err
+		}
+		if changed {
+			columns := map[string]any{}
+			if updateReq.Name != "" {
+				list.Name = updateReq.Name
+				columns["name"] = list.Name
+			}
+			// Lowering the limit below the cards already in the list leaves them
+			// there; the list shows as over its limit until enough leave
+			if updateReq.WIPLimit.Set {
+				list.WIPLimit = updateReq.WIPLimit.Ptr()
+				columns["wip_limit"] = list.WIPLimit
+			}
+			if updateReq.FlowState.Set {
+				list.FlowState = updateReq.FlowState.Value
+				columns["flow_state"] = list.FlowState
+			}
+			list.UpdatedAt = time.Now()
+			columns["updated_at"] = list.UpdatedAt
+			if err := tx.Model(list).UpdateColumns(columns).Error; err != nil {
+				return err
 			}
-			return nil, fmt.Errorf("failed to update list name: %w", err)
 		}
-	}
-
-	if updateReq.Position != nil {
-		if err := s.MoveList(listID, *updateReq.Position); err != nil {
+		if updateReq.Position == nil {
+			return nil
+		}
+		moved, err = s.moveList(tx, list, *updateReq.Position)
+		return err
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return nil, errors.New("list name already exists within this board")
+		}
+		if errors.Is(err, errVersionMismatch) {
 			return nil, err
 		}
+		return nil, fmt.Errorf("failed to update list: %w", err)
+	}
+	if changed {
+		publishBoardEvent(list.BoardID, models.EventListUpdated, actorID, list)
+	}
+	if moved {
+		publishBoardEvent(list.BoardID, models.EventListMoved, actorID, models.ListMovedEvent{ListID: listID, Rank: list.Rank})
 	}
 
 	return s.GetListByID(listID)
 }
 
-func (s *ListService) DeleteList(listID string) error {
-	// In a transaction, delete the list and re-order the remaining lists
+// DeleteList moves the list to the trash together with its cards. It keeps
+// its rank, so restoring it puts it back between the same neighbours.
+func (s *ListService) DeleteList(listID string, version int, actorID string) (*models.DeletionSummary, error) {
 	tx := database.DB.Begin()
 	if tx.Error != nil {
-		return fmt.Errorf("failed to start transaction: %w", tx.Error)
+		return nil, fmt.Errorf("failed to start transaction: %w", tx.Error)
 	}
 
 	var listToDelete models.List
 	if err := tx.First(&listToDelete, "id = ?", listID).Error; err != nil {
 		tx.Rollback()
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("list not found")
+			return nil, errors.New("list not found or already deleted")
 		}
-		return fmt.Errorf("failed to find list to delete: %w", err)
+		return nil, fmt.Errorf("failed to find list to delete: %w", err)
 	}
 
-	if err := tx.Delete(&models.List{}, "id = ?", listID).Error; err != nil {
+	if err := claimVersion(tx, &listToDelete, &listToDelete.Version, version); err != nil {
 		tx.Rollback()
-		return fmt.Errorf("failed to delete list: %w", err)
+		return nil, err
 	}
 
-	// Update positions of subsequent lists
-	if err := tx.Model(&models.List{}).Where("board_id = ? AND position > ?", listToDelete.BoardID, listToDelete.Position).Update("position", gorm.Expr("position - 1")).Error; err != nil {
+	if err := tx.Delete(&models.List{}, "id = ?", listID).Error; err != nil {
 		tx.Rollback()
-		return fmt.Errorf("failed to update positions of subsequent lists: %w", err)
+		return nil, fmt.Errorf("failed to delete list: %w", err)
 	}
 
-	log.Printf("List deleted: ID %s\n", listID)
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	log.Printf("List moved to trash: ID %s\n", listID)
+	publishBoardEvent(listToDelete.BoardID, models.EventListDeleted, actorID, models.ListDeletedEvent{ListID: listID})
+	return &models.DeletionSummary{Lists: 1}, nil
 }
 
-func (s *ListService) MoveList(listID string, newPosition int) error {
+// MoveList puts the list at a 1-based position on its board, or at the end
+// if the position is past it. Only the moved list's rank changes.
+func (s *ListService) MoveList(listID string, newPosition int, actorID string) error {
 	if newPosition < 1 {
 		return errors.New("invalid position: must be greater than 0")
 	}
 
-	tx := database.DB.Begin()
-	if tx.Error != nil {
-		return fmt.Errorf("failed to start transaction: %w", tx.Error)
+	var list models.List
+	moved := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&list, "id = ?", listID).Error; err != nil {
+			return errors.New("list not found")
+		}
+		var err error
+		moved, err = s.moveList(tx, &list, newPosition)
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
-	var list models.List
-	if err := tx.First(&list, "id = ?", listID).Error; err != nil {
-		tx.Rollback()
-		return errors.New("list not found")
+	if moved {
+		publishBoardEvent(list.BoardID, models.EventListMoved, actorID, models.ListMovedEvent{ListID: listID, Rank: list.Rank})
 	}
+	return nil
+}
 
-	oldPosition := list.Position
-	if oldPosition == newPosition {
-		tx.Rollback()
-		return nil // No change
+// moveList ranks the list at a 1-based position on its board inside tx and
+// reports whether its rank changed. The rank is read again in tx, since
+// rebalancing changes it without a new version.
+func (s *ListService) moveList(tx *gorm.DB, list *models.List, newPosition int) (bool, error) {
+	if err := tx.Model(&models.List{}).Select("rank").Where("id = ?", list.ID).Take(&list.Rank).Error; err != nil {
+		return false, fmt.Errorf("failed to retrieve list rank: %w", err)
 	}
 
-	// Shift items between old and new positions
-	if oldPosition < newPosition {
-		if err := tx.Model(&models.List{}).Where("board_id = ? AND position > ? AND position <= ?", list.BoardID, oldPosition, newPosition).Update("position", gorm.Expr("position - 1")).Error; err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to shift lists down: %w", err)
-		}
-	} else {
-		if err := tx.Model(&models.List{}).Where("board_id = ? AND position >= ? AND position < ?", list.BoardID, newPosition, oldPosition).Update("position", gorm.Expr("position + 1")).Error; err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to shift lists up: %w", err)
-		}
+	rank, err := rankAt(tx, &models.List{}, "board_id", list.BoardID, list.ID, list.Rank, newPosition)
+	if err != nil {
+		return false, fmt.Errorf("failed to rank list: %w", err)
+	}
+	if rank == list.Rank {
+		return false, nil
 	}
 
-	list.Position = newPosition
+	list.Rank = rank
 	list.UpdatedAt = time.Now()
-	if err := tx.Save(&list).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to update list position: %w", err)
+	if err := tx.Model(list).UpdateColumns(map[string]any{"rank": rank, "updated_at": list.UpdatedAt}).Error; err != nil {
+		return false, fmt.Errorf("failed to update list rank: %w", err)
 	}
+	return true, nil
+}
 
-	return tx.Commit().Error
+// ArchiveList takes the list and its cards off the board without deleting
+// them. The cards keep their own archived state, so unarchiving the list
+// brings back the cards that were on it.
+func (s *ListService) ArchiveList(listID string, version int, actorID string) (*models.List, error) {
+	return s.changeArchived(listID, version, actorID, true)
 }
 
+// UnarchiveList puts an archived list back on its board where it was.
+func (s *ListService) UnarchiveList(listID string, version int, actorID string) (*models.List, error) {
+	return s.changeArchived(listID, version, actorID, false)
+}
+
+func (s *ListService) changeArchived(listID string, version int, actorID string, archive bool) (*models.List, error) {
+	var list models.List
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&list, "id = ?", listID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("list not found")
+			}
+			return fmt.Errorf("failed to retrieve list: %w", err)
+		}
+		if archive && list.ArchivedAt != nil {
+			return errors.New("list is already archived")
+		}
+		if !archive && list.ArchivedAt == nil {
+			return errors.New("list is not archived")
+		}
+		if err := claimVersion(tx, &list, &list.Version, version); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		list.ArchivedAt = nil
+		if archive {
+			list.ArchivedAt = &now
+		}
+		list.UpdatedAt = now
+		if err := tx.Model(&list).UpdateColumns(map[string]any{"archived_at": list.ArchivedAt, "updated_at": now}).Error; err != nil {
+			return fmt.Errorf("failed to update list: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eventType := models.EventListUnarchived
+	if archive {
+		eventType = models.EventListArchived
+	}
+	log.Printf("List %s: ID %s by user %s\n", strings.TrimPrefix(eventType, "list."), listID, actorID)
+	publishBoardEvent(list.BoardID, eventType, actorID, list)
+	return &list, nil
+}
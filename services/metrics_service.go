@@ -0,0 +1,278 @@
+package services
+
+import (
+	"fmt"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+type MetricsService struct{}
+
+func NewMetricsService() *MetricsService {
+	return &MetricsService{}
+}
+
+// GetBoardMetrics reports on the board's lists that aren't archived and the
+// flow of cards through them over the query's window of days. Days run in
+// UTC, and the window ends with today.
+func (s *MetricsService) GetBoardMetrics(boardID string, query models.MetricsQuery) (*models.BoardMetrics, error) {
+	query = query.Normalize()
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, 1-query.Days)
+
+	var lists []models.List
+	result := database.DB.Where("board_id = ? AND archived_at IS NULL", boardID).Order(rankOrder).Find(&lists)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve lists: %w", result.Error)
+	}
+	states := flowStates(lists)
+
+	wip, err := s.listWIP(lists, states)
+	if err != nil {
+		return nil, err
+	}
+	flows, err := s.cardFlows(lists, states)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := models.BoardMetrics{
+		BoardID:        boardID,
+		Since:          since,
+		WIP:            wip,
+		CumulativeFlow: cumulativeFlow(lists, states, flows, since, now),
+		Throughput:     weeklyThroughput(flows, since, now),
+		AgingWIP:       []models.AgingCard{},
+	}
+
+	var leadTimes, cycleTimes []float64
+	for _, flow := range flows {
+		if flow.doneAt != nil && !flow.doneAt.Before(since) {
+			leadTimes = append(leadTimes, days(flow.doneAt.Sub(flow.card.CreatedAt)))
+			cycleTimes = append(cycleTimes, days(flow.doneAt.Sub(*flow.startedAt)))
+		}
+		if flow.current && flow.card.ArchivedAt == nil && states[flow.card.ListID] == models.FlowStateStarted && flow.startedAt != nil {
+			metrics.AgingWIP = append(metrics.AgingWIP, models.AgingCard{
+				CardID:    flow.card.ID,
+				Title:     flow.card.Title,
+				ListID:    flow.card.ListID,
+				StartedAt: *flow.startedAt,
+				AgeDays:   days(now.Sub(*flow.startedAt)),
+			})
+		}
+	}
+	metrics.LeadTime = flowTimeStats(leadTimes)
+	metrics.CycleTime = flowTimeStats(cycleTimes)
+	sort.SliceStable(metrics.AgingWIP, func(i, j int) bool {
+		return metrics.AgingWIP[i].AgeDays > metrics.AgingWIP[j].AgeDays
+	})
+	return &metrics, nil
+}
+
+// flowStates maps each list to the flow state it counts in. Lists without
+// one count by position when none on the board has one, and as not started
+// otherwise.
+func flowStates(lists []models.List) map[string]string {
+	states := make(map[string]string, len(lists))
+	configured := slices.ContainsFunc(lists, func(l models.List) bool { return l.FlowState != "" })
+	for i, list := range lists {
+		switch {
+		case list.FlowState != "":
+			states[list.ID] = list.FlowState
+		case configured || i == 0:
+			states[list.ID] = models.FlowStateNotStarted
+		case i == len(lists)-1:
+			states[list.ID] = models.FlowStateDone
+		default:
+			states[list.ID] = models.FlowStateStarted
+		}
+	}
+	return states
+}
+
+// listWIP counts the unarchived cards of each list against its WIP limit.
+func (s *MetricsService) listWIP(lists []models.List, states map[string]string) ([]models.ListWIP, error) {
+	var counts []struct {
+		ListID string
+		Cards  int64
+	}
+	result := database.DB.Model(&models.Card{}).
+		Select("list_id, COUNT(*) AS cards").
+		Where("list_id IN ? AND archived_at IS NULL", listIDs(lists)).
+		Group("list_id").
+		Scan(&counts)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count cards: %w", result.Error)
+	}
+	cardsByList := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		cardsByList[count.ListID] = count.Cards
+	}
+
+	wip := make([]models.ListWIP, len(lists))
+	for i, list := range lists {
+		wip[i] = models.ListWIP{
+			ListID:    list.ID,
+			Name:      list.Name,
+			FlowState: states[list.ID],
+			Cards:     cardsByList[list.ID],
+			WIPLimit:  list.WIPLimit,
+		}
+		wip[i].OverWIPLimit = list.WIPLimit != nil && wip[i].Cards > int64(*list.WIPLimit)
+	}
+	return wip, nil
+}
+
+// cardFlow is a card's way through the board's lists. Only stays in the
+// lists are kept, so a card that moved to another board keeps its stays
+// here but is no longer current.
+type cardFlow struct {
+	card  models.Card
+	stays []models.CardListStay
+	// current is set when the card is in one of the lists.
+	current bool
+	// startedAt is when the card first entered a started or done list.
+	startedAt *time.Time
+	// doneAt is when the card entered the done lists it is still in.
+	doneAt *time.Time
+}
+
+// cardFlows loads the stays in the lists with the cards that made them,
+// leaving out cards in the trash.
+func (s *MetricsService) cardFlows(lists []models.List, states map[string]string) ([]*cardFlow, error) {
+	ids := listIDs(lists)
+
+	var stays []models.CardListStay
+	result := database.DB.Where("list_id IN ?", ids).Order("entered_at ASC, id ASC").Find(&stays)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve list stays: %w", result.Error)
+	}
+
+	var cards []models.Card
+	visitors := database.DB.Model(&models.CardListStay{}).Select("card_id").Where("list_id IN ?", ids)
+	result = database.DB.Where("id IN (?) OR list_id IN ?", visitors, ids).Order("created_at ASC, id ASC").Find(&cards)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve cards: %w", result.Error)
+	}
+
+	flows := make([]*cardFlow, len(cards))
+	byCard := make(map[string]*cardFlow, len(cards))
+	for i, card := range cards {
+		_, current := states[card.ListID]
+		flows[i] = &cardFlow{card: card, current: current}
+		byCard[card.ID] = flows[i]
+	}
+	for _, stay := range stays {
+		if flow, ok := byCard[stay.CardID]; ok {
+			flow.stays = append(flow.stays, stay)
+		}
+	}
+
+	for _, flow := range flows {
+		for i := range flow.stays {
+			if states[flow.stays[i].ListID] != models.FlowStateNotStarted {
+				flow.startedAt = &flow.stays[i].EnteredAt
+				break
+			}
+		}
+		if !flow.current {
+			continue
+		}
+		// The card is done from when it entered the run of done lists it
+		// ends with
+		for i := len(flow.stays) - 1; i >= 0 && states[flow.stays[i].ListID] == models.FlowStateDone; i-- {
+			if i < len(flow.stays)-1 && (flow.stays[i].ExitedAt == nil || !flow.stays[i].ExitedAt.Equal(flow.stays[i+1].EnteredAt)) {
+				break
+			}
+			flow.doneAt = &flow.stays[i].EnteredAt
+		}
+	}
+	return flows, nil
+}
+
+// cumulativeFlow counts the cards in each list at the end of every day from
+// since until now, ending today at now.
+func cumulativeFlow(lists []models.List, states map[string]string, flows []*cardFlow, since, now time.Time) []models.CumulativeFlowDay {
+	var cfd []models.CumulativeFlowDay
+	for day := since; !day.After(now); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		if end.After(now) {
+			end = now
+		}
+		counts := make(map[string]int, len(lists))
+		for _, list := range lists {
+			counts[list.ID] = 0
+		}
+		for _, flow := range flows {
+			archived := flow.card.ArchivedAt != nil && !flow.card.ArchivedAt.After(end)
+			for _, stay := range flow.stays {
+				if stay.EnteredAt.After(end) || (stay.ExitedAt != nil && !stay.ExitedAt.After(end)) {
+					continue
+				}
+				if archived && states[stay.ListID] != models.FlowStateDone {
+					continue
+				}
+				counts[stay.ListID]++
+			}
+		}
+		cfd = append(cfd, models.CumulativeFlowDay{Date: day.Format(time.DateOnly), Lists: counts})
+	}
+	return cfd
+}
+
+// weeklyThroughput counts the cards done in each week from the one holding
+// since until the one holding now. Weeks start on Monday.
+func weeklyThroughput(flows []*cardFlow, since, now time.Time) []models.WeeklyThroughput {
+	weekStart := func(t time.Time) time.Time {
+		day := t.UTC().Truncate(24 * time.Hour)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+
+	counts := map[time.Time]int{}
+	for _, flow := range flows {
+		if flow.doneAt != nil && !flow.doneAt.Before(since) {
+			counts[weekStart(*flow.doneAt)]++
+		}
+	}
+
+	var weeks []models.WeeklyThroughput
+	for week := weekStart(since); !week.After(now); week = week.AddDate(0, 0, 7) {
+		weeks = append(weeks, models.WeeklyThroughput{WeekStart: week.Format(time.DateOnly), Cards: counts[week]})
+	}
+	return weeks
+}
+
+// flowTimeStats summarizes durations in days by nearest-rank percentiles.
+func flowTimeStats(durations []float64) models.FlowTimeStats {
+	stats := models.FlowTimeStats{Cards: len(durations)}
+	if len(durations) == 0 {
+		return stats
+	}
+	sort.Float64s(durations)
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p / 100 * float64(len(durations))))
+		return durations[max(rank, 1)-1]
+	}
+	stats.P50 = percentile(50)
+	stats.P85 = percentile(85)
+	stats.P95 = percentile(95)
+	return stats
+}
+
+// days converts a duration to days, rounded to hundredths.
+func days(d time.Duration) float64 {
+	return math.Round(d.Hours()/24*100) / 100
+}
+
+func listIDs(lists []models.List) []string {
+	ids := make([]string, len(lists))
+	for i, list := range lists {
+		ids[i] = list.ID
+	}
+	return ids
+}
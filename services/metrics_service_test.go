@@ -0,0 +1,211 @@
+package services
+
+import (
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestFlowTimeStats(t *testing.T) {
+	tests := []struct {
+		name      string
+		durations []float64
+		want      models.FlowTimeStats
+	}{
+		{"no cards", nil, models.FlowTimeStats{}},
+		{"one card", []float64{2.5}, models.FlowTimeStats{Cards: 1, P50: 2.5, P85: 2.5, P95: 2.5}},
+		{"two cards", []float64{8, 4}, models.FlowTimeStats{Cards: 2, P50: 4, P85: 8, P95: 8}},
+		{"ten cards", []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, models.FlowTimeStats{Cards: 10, P50: 5, P85: 9, P95: 10}},
+		{"twenty cards", []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}, models.FlowTimeStats{Cards: 20, P50: 10, P85: 17, P95: 19}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := flowTimeStats(tt.durations); got != tt.want {
+				t.Errorf("flowTimeStats(%v) = %+v, want %+v", tt.durations, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlowStates(t *testing.T) {
+	tests := []struct {
+		name       string
+		flowStates []string
+		want       []string
+	}{
+		{"one list", []string{""}, []string{models.FlowStateNotStarted}},
+		{"by position", []string{"", "", "", ""},
+			[]string{models.FlowStateNotStarted, models.FlowStateStarted, models.FlowStateStarted, models.FlowStateDone}},
+		{"configured", []string{"", models.FlowStateStarted, "", models.FlowStateDone},
+			[]string{models.FlowStateNotStarted, models.FlowStateStarted, models.FlowStateNotStarted, models.FlowStateDone}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lists := make([]models.List, len(tt.flowStates))
+			for i, state := range tt.flowStates {
+				lists[i] = models.List{ID: string(rune('a' + i)), FlowState: state}
+			}
+			states := flowStates(lists)
+			for i, list := range lists {
+				if states[list.ID] != tt.want[i] {
+					t.Errorf("list %d counts as %q, want %q", i, states[list.ID], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCumulativeFlow(t *testing.T) {
+	since := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time { return since.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	lists := []models.List{{ID: "todo"}, {ID: "done"}}
+	states := map[string]string{"todo": models.FlowStateNotStarted, "done": models.FlowStateDone}
+	flows := []*cardFlow{
+		// Finished on the second day
+		{card: models.Card{ListID: "done"}, stays: []models.CardListStay{
+			{ListID: "todo", EnteredAt: at(0, 10), ExitedAt: ptr(at(1, 10))},
+			{ListID: "done", EnteredAt: at(1, 10)},
+		}},
+		// Archived from the backlog on the second day
+		{card: models.Card{ListID: "todo", ArchivedAt: ptr(at(1, 12))}, stays: []models.CardListStay{
+			{ListID: "todo", EnteredAt: at(0, 9)},
+		}},
+		// Archived once done, so it keeps counting
+		{card: models.Card{ListID: "done", ArchivedAt: ptr(at(1, 12))}, stays: []models.CardListStay{
+			{ListID: "done", EnteredAt: at(0, 9)},
+		}},
+		// Added on the last day, after the window ends
+		{card: models.Card{ListID: "todo"}, stays: []models.CardListStay{
+			{ListID: "todo", EnteredAt: at(2, 18)},
+		}},
+	}
+
+	want := []models.CumulativeFlowDay{
+		{Date: "2026-03-02", Lists: map[string]int{"todo": 2, "done": 1}},
+		{Date: "2026-03-03", Lists: map[string]int{"todo": 0, "done": 2}},
+		{Date: "2026-03-04", Lists: map[string]int{"todo": 0, "done": 2}},
+	}
+	if got := cumulativeFlow(lists, states, flows, since, at(2, 12)); !reflect.DeepEqual(got, want) {
+		t.Errorf("cumulativeFlow = %+v, want %+v", got, want)
+	}
+}
+
+func TestWeeklyThroughput(t *testing.T) {
+	// A Wednesday, so the window starts in the week of Monday the 2nd
+	since := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 3, 17, 12, 0, 0, 0, time.UTC)
+	done := func(t time.Time) *cardFlow { return &cardFlow{doneAt: &t} }
+	flows := []*cardFlow{
+		done(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)), // before the window
+		done(time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)),
+		done(time.Date(2026, 3, 8, 23, 0, 0, 0, time.UTC)),
+		done(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)),
+		{}, // not done
+		done(time.Date(2026, 3, 17, 9, 0, 0, 0, time.UTC)),
+	}
+
+	want := []models.WeeklyThroughput{
+		{WeekStart: "2026-03-02", Cards: 2},
+		{WeekStart: "2026-03-09", Cards: 1},
+		{WeekStart: "2026-03-16", Cards: 1},
+	}
+	if got := weeklyThroughput(flows, since, now); !reflect.DeepEqual(got, want) {
+		t.Errorf("weeklyThroughput = %+v, want %+v", got, want)
+	}
+}
+
+// testStay is a card entering a list some days ago.
+type testStay struct {
+	list    string
+	daysAgo float64
+}
+
+// backdateCard rewrites a card's history: it was created when it entered the
+// first list and ends in the last.
+func backdateCard(t *testing.T, cardID string, now time.Time, stays ...testStay) {
+	t.Helper()
+	at := func(daysAgo float64) time.Time { return now.Add(-time.Duration(daysAgo * float64(24*time.Hour))) }
+
+	if err := database.DB.Where("card_id = ?", cardID).Delete(&models.CardListStay{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	for i, stay := range stays {
+		row := models.CardListStay{ID: uuid.New().String(), CardID: cardID, ListID: stay.list, EnteredAt: at(stay.daysAgo)}
+		if i+1 < len(stays) {
+			exited := at(stays[i+1].daysAgo)
+			row.ExitedAt = &exited
+		}
+		if err := database.DB.Create(&row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	last := stays[len(stays)-1]
+	err := database.DB.Model(&models.Card{}).Where("id = ?", cardID).UpdateColumns(map[string]any{
+		"list_id":         last.list,
+		"created_at":      at(stays[0].daysAgo),
+		"list_entered_at": at(last.daysAgo),
+	}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetBoardMetrics(t *testing.T) {
+	b := newTestBoard(t)
+	todo := addTestList(t, b, "To Do")
+	doing := addTestList(t, b, "Doing")
+	done := addTestList(t, b, "Done")
+	now := time.Now()
+
+	fast := addTestCard(t, b, todo, "Fast")
+	backdateCard(t, fast, now, testStay{todo, 5}, testStay{doing, 4}, testStay{done, 1})
+	slow := addTestCard(t, b, todo, "Slow")
+	backdateCard(t, slow, now, testStay{todo, 10}, testStay{doing, 6}, testStay{done, 2})
+	// Reopened and finished again: only its last time in done counts
+	reopened := addTestCard(t, b, todo, "Reopened")
+	backdateCard(t, reopened, now, testStay{todo, 40}, testStay{doing, 39}, testStay{done, 38}, testStay{doing, 3}, testStay{done, 0.5})
+	aging := addTestCard(t, b, todo, "Aging")
+	backdateCard(t, aging, now, testStay{todo, 3}, testStay{doing, 2})
+	addTestCard(t, b, todo, "Waiting")
+
+	metrics, err := NewMetricsService().GetBoardMetrics(b.BoardID, models.MetricsQuery{Days: 14})
+	if err != nil {
+		t.Fatalf("GetBoardMetrics: %v", err)
+	}
+
+	// Lead times are 4, 8 and 39.5 days; cycle times 3, 4 and 38.5 days
+	if want := (models.FlowTimeStats{Cards: 3, P50: 8, P85: 39.5, P95: 39.5}); metrics.LeadTime != want {
+		t.Errorf("lead time = %+v, want %+v", metrics.LeadTime, want)
+	}
+	if want := (models.FlowTimeStats{Cards: 3, P50: 4, P85: 38.5, P95: 38.5}); metrics.CycleTime != want {
+		t.Errorf("cycle time = %+v, want %+v", metrics.CycleTime, want)
+	}
+
+	if len(metrics.CumulativeFlow) != 14 {
+		t.Fatalf("got %d days of cumulative flow, want 14", len(metrics.CumulativeFlow))
+	}
+	if got, want := metrics.CumulativeFlow[0].Lists, map[string]int{todo: 0, doing: 0, done: 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("first day = %v, want %v", got, want)
+	}
+	if got, want := metrics.CumulativeFlow[13].Lists, map[string]int{todo: 1, doing: 1, done: 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("today = %v, want %v", got, want)
+	}
+
+	throughput := 0
+	for _, week := range metrics.Throughput {
+		throughput += week.Cards
+	}
+	if throughput != 3 {
+		t.Errorf("throughput = %d cards, want 3", throughput)
+	}
+
+	if len(metrics.AgingWIP) != 1 || metrics.AgingWIP[0].CardID != aging || metrics.AgingWIP[0].AgeDays != 2 {
+		t.Errorf("aging WIP = %+v, want the aging card at 2 days", metrics.AgingWIP)
+	}
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kanban-app/api/auth"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrganizationMemberService struct{}
+
+func NewOrganizationMemberService() *OrganizationMemberService {
+	return &OrganizationMemberService{}
+}
+
+func (s *OrganizationMemberService) InviteMember(orgID, inviterID string, req models.InviteMemberRequest) (*models.OrganizationMember, error) {
+	var user models.User
+	query := database.DB.Where("email = ?", req.Email)
+	if req.Email == "" {
+		query = database.DB.Where("username = ?", req.Username)
+	}
+	if err := query.First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, fmt.Errorf("failed to find user to invite: %w", err)
+	}
+
+	var member models.OrganizationMember
+	err := database.DB.Where("organization_id = ? AND user_id = ?", orgID, user.ID).First(&member).Error
+	switch {
+	case err == nil && member.Status == models.MemberStatusAccepted:
+		return nil, errors.New("user is already a member of this organization")
+	case err == nil && member.Status == models.MemberStatusPending:
+		return nil, errors.New("user already has a pending invitation")
+	case err == nil:
+		// A declined invitation can be re-issued.
+		member.Role = req.Role
+		member.Status = models.MemberStatusPending
+		member.InvitedBy = inviterID
+		member.UpdatedAt = time.Now()
+		if err := database.DB.Omit(clause.Associations).Save(&member).Error; err != nil {
+			return nil, fmt.Errorf("failed to re-invite member: %w", err)
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		member = models.OrganizationMember{
+			ID:             uuid.New().String(),
+			OrganizationID: orgID,
+			UserID:         user.ID,
+			Role:           req.Role,
+			Status:         models.MemberStatusPending,
+			InvitedBy:      inviterID,
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		}
+		if err := database.DB.Create(&member).Error; err != nil {
+			return nil, fmt.Errorf("failed to create invitation: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("failed to check existing membership: %w", err)
+	}
+
+	log.Printf("User %s invited to organization %s as %s by %s\n", user.ID, orgID, member.Role, inviterID)
+	member.User = &user
+	return &member, nil
+}
+
+func (s *OrganizationMemberService) GetMembers(orgID string) ([]models.OrganizationMember, error) {
+	var members []models.OrganizationMember
+	result := database.DB.Preload("User").Where("organization_id = ?", orgID).Order("created_at ASC").Find(&members)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve members: %w", result.Error)
+	}
+	return members, nil
+}
+
+func (s *OrganizationMemberService) GetMemberByID(orgID, memberID string) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	result := database.DB.Preload("User").Where("organization_id = ?", orgID).First(&member, "id = ?", memberID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("member not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve member: %w", result.Error)
+	}
+	return &member, nil
+}
+
+// GetAcceptedMember returns the membership of a user who has joined the
+// organization, with the user preloaded.
+func (s *OrganizationMemberService) GetAcceptedMember(orgID, userID string) (*models.OrganizationMember, error) {
+	return s.getAcceptedMember(database.DB, orgID, userID)
+}
+
+// getAcceptedMember is GetAcceptedMember reading through db, for checks made
+// inside a transaction.
+func (s *OrganizationMemberService) getAcceptedMember(db *gorm.DB, orgID, userID string) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	err := db.Preload("User").
+		Where("organization_id = ? AND user_id = ? AND status = ?", orgID, userID, models.MemberStatusAccepted).
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user is not a member of this organization")
+		}
+		return nil, fmt.Errorf("failed to check membership: %w", err)
+	}
+	return &member, nil
+}
+
+func (s *OrganizationMemberService) GetInvitationsForUser(userID string) ([]models.OrganizationMember, error) {
+	var invitations []models.OrganizationMember
+	result := database.DB.Preload("Organization").Where("user_id = ? AND status = ?", userID, models.MemberStatusPending).Find(&invitations)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve invitations: %w", result.Error)
+	}
+	return invitations, nil
+}
+
+func (s *OrganizationMemberService) AcceptInvitation(invitationID, userID string) (*models.OrganizationMember, error) {
+	member, err := s.getPendingInvitation(invitationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	member.Status = models.MemberStatusAccepted
+	member.UpdatedAt = time.Now()
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, member, &member.Version, AnyVersion); err != nil {
+			return err
+		}
+		return tx.Model(member).UpdateColumns(map[string]any{"status": member.Status, "updated_at": member.UpdatedAt}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	if _, err := auth.NewAuthorizationService().WithActor(userID).AddPolicy(userID, member.OrganizationID, member.Role); err != nil {
+		return nil, fmt.Errorf("failed to add policy for new member: %w", err)
+	}
+
+	log.Printf("User %s joined organization %s as %s\n", userID, member.OrganizationID, member.Role)
+	return member, nil
+}
+
+func (s *OrganizationMemberService) DeclineInvitation(invitationID, userID string) (*models.OrganizationMember, error) {
+	member, err := s.getPendingInvitation(invitationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	member.Status = models.MemberStatusDeclined
+	member.UpdatedAt = time.Now()
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, member, &member.Version, AnyVersion); err != nil {
+			return err
+		}
+		return tx.Model(member).UpdateColumns(map[string]any{"status": member.Status, "updated_at": member.UpdatedAt}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decline invitation: %w", err)
+	}
+	return member, nil
+}
+
+func (s *OrganizationMemberService) UpdateMemberRole(orgID, memberID string, version int, actorID, role string) (*models.OrganizationMember, error) {
+	member, err := s.GetMemberByID(orgID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	// Claiming the version below makes sure the member is still as read here
+	oldRole := member.Role
+	if oldRole != role {
+		if err := s.checkOwnerChange(orgID, actorID, member, role); err != nil {
+			return nil, err
+		}
+	}
+
+	member.Role = role
+	member.UpdatedAt = time.Now()
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, member, &member.Version, version); err != nil {
+			return err
+		}
+		return tx.Model(member).UpdateColumns(map[string]any{"role": member.Role, "updated_at": member.UpdatedAt}).Error
+	})
+	if err != nil {
+		if errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update member role: %w", err)
+	}
+	if oldRole == role {
+		return member, nil
+	}
+
+	if member.Status == models.MemberStatusAccepted {
+		authService := auth.NewAuthorizationService().WithActor(actorID)
+		if _, err := authService.RemovePolicy(member.UserID, orgID, oldRole); err != nil {
+			return nil, fmt.Errorf("failed to remove old policy for member: %w", err)
+		}
+		if _, err := authService.AddPolicy(member.UserID, orgID, role); err != nil {
+			return nil, fmt.Errorf("failed to add policy for member: %w", err)
+		}
+	}
+
+	log.Printf("Member %s of organization %s changed role from %s to %s\n", member.UserID, orgID, oldRole, role)
+	return member, nil
+}
+
+func (s *OrganizationMemberService) RemoveMember(orgID, memberID string, version int, actorID string) error {
+	member, err := s.GetMemberByID(orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkOwnerChange(orgID, actorID, member, ""); err != nil {
+		return err
+	}
+
+	// Former members can no longer be assigned to or watch the organization's
+	// cards, nor be assigned their checklist items
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, member, &member.Version, version); err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.OrganizationMember{}, "id = ?", member.ID).Error; err != nil {
+			return err
+		}
+		orgCards := tx.Table("cards").Select("cards.id").
+			Joins("JOIN lists ON lists.id = cards.list_id").
+			Joins("JOIN boards ON boards.id = lists.board_id").
+			Joins("JOIN projects ON projects.id = boards.project_id").
+			Where("projects.organization_id = ?", orgID)
+		for _, table := range []string{"card_assignees", "card_watchers"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE user_id = ? AND card_id IN (?)", member.UserID, orgCards).Error; err != nil {
+				return err
+			}
+		}
+		orgChecklists := tx.Model(&models.Checklist{}).Select("id").Where("card_id IN (?)", orgCards)
+		return tx.Model(&models.ChecklistItem{}).
+			Where("assignee_id = ? AND checklist_id IN (?)", member.UserID, orgChecklists).
+			UpdateColumns(map[string]any{"assignee_id": nil, "updated_at": time.Now(), "version": gorm.Expr("version + 1")}).Error
+	})
+	if err != nil {
+		if errors.Is(err, errVersionMismatch) {
+			return err
+		}
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+
+	if member.Status == models.MemberStatusAccepted {
+		if _, err := auth.NewAuthorizationService().WithActor(actorID).RemovePolicy(member.UserID, orgID, member.Role); err != nil {
+			return fmt.Errorf("failed to remove policy for member: %w", err)
+		}
+	}
+	if err := s.removeResourceRoles(orgID, member.UserID, actorID); err != nil {
+		return err
+	}
+
+	log.Printf("Member %s removed from organization %s\n", member.UserID, orgID)
+	return nil
+}
+
+// removeResourceRoles removes the roles granted to the user directly on the
+// organization's projects and boards, including those in the trash, which
+// would otherwise outlast the membership.
+func (s *OrganizationMemberService) removeResourceRoles(orgID, userID, actorID string) error {
+	var projectIDs []string
+	if err := database.DB.Unscoped().Model(&models.Project{}).Where("organization_id = ?", orgID).Pluck("id", &projectIDs).Error; err != nil {
+		return fmt.Errorf("failed to find projects: %w", err)
+	}
+	var boardIDs []string
+	if len(projectIDs) > 0 {
+		if err := database.DB.Unscoped().Model(&models.Board{}).Where("project_id IN ?", projectIDs).Pluck("id", &boardIDs).Error; err != nil {
+			return fmt.Errorf("failed to find boards: %w", err)
+		}
+	}
+
+	authService := auth.NewAuthorizationService().WithActor(actorID)
+	for _, resourceID := range append(projectIDs, boardIDs...) {
+		if _, err := authService.RemoveSubjectPolicies(userID, resourceID); err != nil {
+			return fmt.Errorf("failed to remove permissions of member: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *OrganizationMemberService) getPendingInvitation(invitationID, userID string) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	result := database.DB.Preload("Organization").Where("user_id = ?", userID).First(&member, "id = ?", invitationID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invitation not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve invitation: %w", result.Error)
+	}
+	if member.Status != models.MemberStatusPending {
+		return nil, errors.New("invitation is no longer pending")
+	}
+	return &member, nil
+}
+
+// checkOwnerChange guards the owner role: only owners may grant or revoke it,
+// and an organization can never be left without an accepted owner.
+func (s *OrganizationMemberService) checkOwnerChange(orgID, actorID string, member *models.OrganizationMember, newRole string) error {
+	if member.Role != models.RoleOwner && newRole != models.RoleOwner {
+		return nil
+	}
+
+	isOwner, err := auth.NewAuthorizationService().Enforce(actorID, orgID, models.ActionOwn)
+	if err != nil {
+		return fmt.Errorf("failed to check actor role: %w", err)
+	}
+	if !isOwner {
+		return errors.New("only owners can grant or revoke the owner role")
+	}
+
+	if member.Role != models.RoleOwner || member.Status != models.MemberStatusAccepted {
+		return nil
+	}
+
+	var owners int64
+	err = database.DB.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND role = ? AND status = ?", orgID, models.RoleOwner, models.MemberStatusAccepted).
+		Count(&owners).Error
+	if err != nil {
+		return fmt.Errorf("failed to count owners: %w", err)
+	}
+	if owners <= 1 {
+		return errors.New("organization must have at least one owner")
+	}
+	return nil
+}
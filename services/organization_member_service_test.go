@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"kanban-app/api/auth"
+	"kanban-app/api/models"
+	"strings"
+	"testing"
+)
+
+// ownerMember returns the membership of the user who created the
+// organization of b.
+func ownerMember(t *testing.T, b testBoard) *models.OrganizationMember {
+	t.Helper()
+	members, err := NewOrganizationMemberService().GetMembers(b.OrgID)
+	if err != nil {
+		t.Fatalf("GetMembers: %v", err)
+	}
+	for i := range members {
+		if members[i].UserID == b.OwnerID {
+			return &members[i]
+		}
+	}
+	t.Fatal("owner is not a member")
+	return nil
+}
+
+func TestUpdateMemberRoleUnchangedChecksVersion(t *testing.T) {
+	b := newTestBoard(t)
+	members := NewOrganizationMemberService()
+	owner := ownerMember(t, b)
+
+	// Setting the role the member already has is still a write, so a stale
+	// version is refused
+	if _, err := members.UpdateMemberRole(b.OrgID, owner.ID, owner.Version+1, b.OwnerID, models.RoleOwner); !errors.Is(err, errVersionMismatch) {
+		t.Fatalf("UpdateMemberRole with a stale version error = %v, want %v", err, errVersionMismatch)
+	}
+
+	// The only owner keeps the role, rather than being refused as if it were
+	// taken away
+	updated, err := members.UpdateMemberRole(b.OrgID, owner.ID, owner.Version, b.OwnerID, models.RoleOwner)
+	if err != nil {
+		t.Fatalf("UpdateMemberRole with the current version: %v", err)
+	}
+	if updated.Role != models.RoleOwner || updated.Version != owner.Version+1 {
+		t.Errorf("member is %s at version %d, want %s at version %d", updated.Role, updated.Version, models.RoleOwner, owner.Version+1)
+	}
+	isOwner, err := auth.NewAuthorizationService().Enforce(b.OwnerID, b.OrgID, models.ActionOwn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isOwner {
+		t.Error("owner lost the owner role")
+	}
+}
+
+// addTestMember invites a new user into the organization of b with the role,
+// and accepts the invitation.
+func addTestMember(t *testing.T, b testBoard, role string) *models.OrganizationMember {
+	t.Helper()
+	userID := createTestUser(t)
+	members := NewOrganizationMemberService()
+	invitation, err := members.InviteMember(b.OrgID, b.OwnerID, models.InviteMemberRequest{Username: "user-" + userID, Role: role})
+	if err != nil {
+		t.Fatalf("InviteMember: %v", err)
+	}
+	member, err := members.AcceptInvitation(invitation.ID, userID)
+	if err != nil {
+		t.Fatalf("AcceptInvitation: %v", err)
+	}
+	return member
+}
+
+func TestGrantRoleRequiresMember(t *testing.T) {
+	b := newTestBoard(t)
+	permissions := NewPermissionService()
+
+	outsider := createTestUser(t)
+	if _, err := permissions.GrantRole(b.BoardID, outsider, models.RoleViewer, b.OwnerID); err == nil || !strings.Contains(err.Error(), "not a member") {
+		t.Errorf("GrantRole to an outsider error = %v, want not a member", err)
+	}
+
+	member := addTestMember(t, b, models.RoleViewer)
+	if _, err := permissions.GrantRole(b.BoardID, member.UserID, models.RoleMember, b.OwnerID); err != nil {
+		t.Errorf("GrantRole to a member: %v", err)
+	}
+}
+
+func TestRemoveMemberRemovesResourceRoles(t *testing.T) {
+	b := newTestBoard(t)
+	other := newTestBoardIn(t, b)
+	member := addTestMember(t, b, models.RoleViewer)
+
+	authService := auth.NewAuthorizationService()
+	permissions := NewPermissionService()
+	for _, boardID := range []string{b.BoardID, other.BoardID} {
+		if _, err := permissions.GrantRole(boardID, member.UserID, models.RoleMember, b.OwnerID); err != nil {
+			t.Fatalf("GrantRole: %v", err)
+		}
+	}
+	if _, err := authService.AddPolicy(member.UserID, b.ProjectID, models.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	// A board in the trash keeps its roles until purged, and could be restored
+	if _, err := NewBoardService().DeleteBoard(other.BoardID, AnyVersion); err != nil {
+		t.Fatalf("DeleteBoard: %v", err)
+	}
+
+	if err := NewOrganizationMemberService().RemoveMember(b.OrgID, member.ID, AnyVersion, b.OwnerID); err != nil {
+		t.Fatalf("RemoveMember: %v", err)
+	}
+
+	for _, resourceID := range []string{b.OrgID, b.ProjectID, b.BoardID, other.BoardID} {
+		policies, err := authService.GetResourcePolicies(resourceID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, policy := range policies {
+			if policy[0] == member.UserID {
+				t.Errorf("former member still holds %s on %s", policy[2], resourceID)
+			}
+		}
+		can, err := authService.Enforce(member.UserID, resourceID, models.ActionRead)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if can {
+			t.Errorf("former member can still read %s", resourceID)
+		}
+	}
+}
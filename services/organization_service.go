@@ -29,18 +29,34 @@ func (s *OrganizationService) CreateOrganization(name, ownerID string) (*models.
 		UpdatedAt: time.Now(),
 	}
 
-	result := database.DB.Create(&org)
-	if result.Error != nil {
-		if strings.Contains(result.Error.Error(), "UNIQUE constraint failed") && strings.Contains(result.Error.Error(), "organizations.name") {
+	owner := models.OrganizationMember{
+		ID:             uuid.New().String(),
+		OrganizationID: org.ID,
+		UserID:         ownerID,
+		Role:           models.RoleOwner,
+		Status:         models.MemberStatusAccepted,
+		InvitedBy:      ownerID,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&org).Error; err != nil {
+			return err
+		}
+		return tx.Create(&owner).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") && strings.Contains(err.Error(), "organizations.name") {
 			return nil, errors.New("organization name already exists")
 		}
-		return nil, fmt.Errorf("failed to create organization: %w", result.Error)
+		return nil, fmt.Errorf("failed to create organization: %w", err)
 	}
 
 	log.Printf("Organization created: %s by user %s\n", org.Name, org.OwnerID)
 
 	// Add policy to Casbin
-	_, err := auth.NewAuthorizationService().AddPolicy(ownerID, org.ID, "owner")
+	_, err = auth.NewAuthorizationService().WithActor(ownerID).AddPolicy(ownerID, org.ID, models.RoleOwner)
 	if err != nil {
 		return nil, fmt.Errorf("failed to add policy for new organization: %w", err)
 	}
@@ -48,9 +64,11 @@ func (s *OrganizationService) CreateOrganization(name, ownerID string) (*models.
 	return &org, nil
 }
 
-func (s *OrganizationService) GetOrganizationsByUser(ownerID string) ([]models.Organization, error) {
+func (s *OrganizationService) GetOrganizationsByUser(userID string) ([]models.Organization, error) {
 	var organizations []models.Organization
-	result := database.DB.Where("owner_id = ?", ownerID).Find(&organizations)
+	result := database.DB.Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ? AND organization_members.status = ?", userID, models.MemberStatusAccepted).
+		Find(&organizations)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to retrieve organizations: %w", result.Error)
 	}
@@ -69,36 +87,79 @@ func (s *OrganizationService) GetOrganizationByID(orgID string) (*models.Organiz
 	return &organization, nil
 }
 
-func (s *OrganizationService) UpdateOrganization(orgID string, updateReq models.UpdateOrganizationRequest) (*models.Organization, error) {
+func (s *OrganizationService) UpdateOrganization(orgID string, version int, updateReq models.UpdateOrganizationRequest) (*models.Organization, error) {
 	org, err := s.GetOrganizationByID(orgID)
 	if err != nil {
 		return nil, err
 	}
 
+	columns := map[string]any{}
 	if updateReq.Name != "" {
 		org.Name = updateReq.Name
+		columns["name"] = org.Name
 	}
 	org.UpdatedAt = time.Now()
+	columns["updated_at"] = org.UpdatedAt
 
-	result := database.DB.Save(&org)
-	if result.Error != nil {
-		if strings.Contains(result.Error.Error(), "UNIQUE constraint failed") && strings.Contains(result.Error.Error(), "organizations.name") {
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, org, &org.Version, version); err != nil {
+			return err
+		}
+		return tx.Model(org).UpdateColumns(columns).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") && strings.Contains(err.Error(), "organizations.name") {
 			return nil, errors.New("organization name already exists")
 		}
-		return nil, fmt.Errorf("failed to update organization: %w", result.Error)
+		if errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update organization: %w", err)
 	}
 	log.Printf("Organization updated: %s (ID: %s)\n", org.Name, org.ID)
 	return org, nil
 }
 
-func (s *OrganizationService) DeleteOrganization(orgID string) error {
-	result := database.DB.Delete(&models.Organization{}, "id = ?", orgID)
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete organization: %w", result.Error)
+func (s *OrganizationService) DeleteOrganization(orgID string, version int, actorID string) (*models.DeletionSummary, error) {
+	var deleter *cascadeDeleter
+	var org models.Organization
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&org, "id = ?", orgID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("organization not found or already deleted")
+			}
+			return err
+		}
+		if err := claimVersion(tx, &org, &org.Version, version); err != nil {
+			return err
+		}
+		deleter = newCascadeDeleter(tx)
+		if err := deleter.deleteOrganizations([]string{orgID}); err != nil {
+			return err
+		}
+		if deleter.summary.Organizations == 0 {
+			return errors.New("organization not found or already deleted")
+		}
+		return nil
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "not found or already deleted") || errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to delete organization: %w", err)
 	}
-	if result.RowsAffected == 0 {
-		return errors.New("organization not found or already deleted")
+
+	if err := deleter.removePolicies(); err != nil {
+		return nil, err
 	}
+
+	auth.NewAuthorizationService().WithActor(actorID).Audit(models.AuditEntry{
+		OrganizationID: orgID,
+		Action:         models.AuditOrganizationDeleted,
+		ResourceID:     orgID,
+		Details:        map[string]any{"name": org.Name, "deleted": deleter.summary},
+	})
+
 	log.Printf("Organization deleted: ID %s\n", orgID)
-	return nil
+	return &deleter.summary, nil
 }
@@ -38,12 +38,12 @@ func (s *ProjectService) CreateProject(organizationID, name, description, userID
 		return nil, fmt.Errorf("failed to create project: %w", result.Error)
 	}
 
-	log.Printf("Project created: %s in organization %s\n", project.Name, project.OrganizationID)
+	log.Printf("Project created: %s in organization %s by user %s\n", project.Name, project.OrganizationID, userID)
 
-	// Add policy to Casbin
-	_, err := auth.NewAuthorizationService().AddPolicy(userID, project.ID, "owner")
+	// Link the project to its organization so organization grants apply to it
+	_, err := auth.NewAuthorizationService().AddResourceParent(project.ID, organizationID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add policy for new project: %w", err)
+		return nil, fmt.Errorf("failed to link new project to organization: %w", err)
 	}
 
 	return &project, nil
@@ -70,39 +70,83 @@ func (s *ProjectService) GetProjectByID(projectID string) (*models.Project, erro
 	return &project, nil
 }
 
-func (s *ProjectService) UpdateProject(projectID string, updateReq models.UpdateProjectRequest) (*models.Project, error) {
+func (s *ProjectService) UpdateProject(projectID string, version int, updateReq models.UpdateProjectRequest) (*models.Project, error) {
 	project, err := s.GetProjectByID(projectID)
 	if err != nil {
 		return nil, err
 	}
 
+	columns := map[string]any{}
 	if updateReq.Name != "" {
 		project.Name = updateReq.Name
+		columns["name"] = project.Name
 	}
 	if updateReq.Description != "" {
 		project.Description = updateReq.Description
+		columns["description"] = project.Description
 	}
 	project.UpdatedAt = time.Now()
+	columns["updated_at"] = project.UpdatedAt
 
-	result := database.DB.Save(&project)
-	if result.Error != nil {
-		if strings.Contains(result.Error.Error(), "UNIQUE constraint failed") && strings.Contains(result.Error.Error(), "projects.name") {
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, project, &project.Version, version); err != nil {
+			return err
+		}
+		return tx.Model(project).UpdateColumns(columns).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") && strings.Contains(err.Error(), "projects.name") {
 			return nil, errors.New("project name already exists within this organization")
 		}
-		return nil, fmt.Errorf("failed to update project: %w", result.Error)
+		if errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update project: %w", err)
 	}
 	log.Printf("Project updated: %s (ID: %s)\n", project.Name, project.ID)
 	return project, nil
 }
 
-func (s *ProjectService) DeleteProject(projectID string) error {
-	result := database.DB.Delete(&models.Project{}, "id = ?", projectID)
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete project: %w", result.Error)
+func (s *ProjectService) DeleteProject(projectID string, version int, actorID string) (*models.DeletionSummary, error) {
+	var deleter *cascadeDeleter
+	var project models.Project
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&project, "id = ?", projectID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("project not found or already deleted")
+			}
+			return err
+		}
+		if err := claimVersion(tx, &project, &project.Version, version); err != nil {
+			return err
+		}
+		deleter = newCascadeDeleter(tx)
+		if err := deleter.deleteProjects([]string{projectID}); err != nil {
+			return err
+		}
+		if deleter.summary.Projects == 0 {
+			return errors.New("project not found or already deleted")
+		}
+		return nil
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "not found or already deleted") || errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to delete project: %w", err)
 	}
-	if result.RowsAffected == 0 {
-		return errors.New("project not found or already deleted")
+
+	if err := deleter.removePolicies(); err != nil {
+		return nil, err
 	}
+
+	auth.NewAuthorizationService().WithActor(actorID).Audit(models.AuditEntry{
+		OrganizationID: project.OrganizationID,
+		Action:         models.AuditProjectDeleted,
+		ResourceID:     projectID,
+		Details:        map[string]any{"name": project.Name, "deleted": deleter.summary},
+	})
+
 	log.Printf("Project deleted: ID %s\n", projectID)
-	return nil
+	return &deleter.summary, nil
 }
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Lists and cards are ordered by rank: a string of base-36 digits read as a
+// fraction, so there is always room for another rank between two neighbours
+// and a move only rewrites the row being moved. Ranks never end in "0", which
+// would leave no room below them, and compare as plain strings.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const (
+	// rankOrder sorts siblings, with the ID breaking ties between equal ranks.
+	rankOrder = "rank ASC, id ASC"
+	// maxRankLength is how long a rank may grow before its siblings are
+	// respaced by the rebalancing job.
+	maxRankLength = 16
+	// rankSpacing is the gap left between ranks when they are respaced, room
+	// for roughly ten moves into the same gap before ranks grow.
+	rankSpacing = 36 * 36
+)
+
+var errRankOrder = errors.New("ranks are out of order")
+
+// rankTarget is a kind of ranked row and the column holding its parent.
+type rankTarget struct {
+	model        any
+	parentColumn string
+}
+
+var rankTargets = []rankTarget{
+	{&models.List{}, "board_id"},
+	{&models.Card{}, "list_id"},
+}
+
+// rankBetween returns a rank after prev and before next. An empty prev is
+// the start of the order and an empty next its end.
+func rankBetween(prev, next string) (string, error) {
+	if next != "" && prev >= next {
+		return "", errRankOrder
+	}
+	return rankMidpoint(prev, next), nil
+}
+
+func rankMidpoint(prev, next string) string {
+	if next != "" {
+		// Keep the digits both ranks share and split the remainder
+		n := 0
+		for n < len(next) && rankDigitAt(prev, n) == next[n] {
+			n++
+		}
+		if n > 0 {
+			return next[:n] + rankMidpoint(rankSuffix(prev, n), next[n:])
+		}
+	}
+
+	lo := strings.IndexByte(rankDigits, rankDigitAt(prev, 0))
+	hi := len(rankDigits)
+	if next != "" {
+		hi = strings.IndexByte(rankDigits, next[0])
+	}
+	if hi-lo > 1 {
+		return string(rankDigits[(lo+hi)/2])
+	}
+	// The first digits are adjacent, so the rank goes one digit deeper
+	if len(next) > 1 {
+		return next[:1]
+	}
+	return string(rankDigits[lo]) + rankMidpoint(rankSuffix(prev, 1), "")
+}
+
+func rankDigitAt(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return rankDigits[0]
+}
+
+func rankSuffix(rank string, n int) string {
+	if n >= len(rank) {
+		return ""
+	}
+	return rank[n:]
+}
+
+// rankSequence returns n evenly spaced ranks in ascending order.
+func rankSequence(n int) []string {
+	width, span := 1, int64(len(rankDigits))
+	for span < int64(n+1)*rankSpacing {
+		width++
+		span *= int64(len(rankDigits))
+	}
+	step := span / int64(n+1)
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		value := step * int64(i+1)
+		digits := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			digits[j] = rankDigits[value%int64(len(rankDigits))]
+			value /= int64(len(rankDigits))
+		}
+		ranks[i] = strings.TrimRight(string(digits), rankDigits[:1])
+	}
+	return ranks
+}
+
+// rankAt returns the rank that puts a row at the 1-based position among the
+// other rows under the same parent, or at the end if position is past it.
+// Archived rows are hidden from board views, so positions don't count them. A
+// row whose current rank already sits there keeps it. Siblings sharing a rank
+// leave no room between them, so they are respaced first.
+func rankAt(tx *gorm.DB, model any, parentColumn, parentID, excludeID, current string, position int) (string, error) {
+	siblings := func() *gorm.DB {
+		return tx.Model(model).Where(parentColumn+" = ? AND id <> ? AND archived_at IS NULL", parentID, excludeID)
+	}
+
+	for attempt := 0; ; attempt++ {
+		var count int64
+		if err := siblings().Count(&count).Error; err != nil {
+			return "", fmt.Errorf("failed to count siblings: %w", err)
+		}
+		position = min(position, int(count)+1)
+
+		offset, limit := position-2, 2
+		if position == 1 {
+			offset, limit = 0, 1
+		}
+		var neighbours []string
+		if err := siblings().Order(rankOrder).Offset(offset).Limit(limit).Pluck("rank", &neighbours).Error; err != nil {
+			return "", fmt.Errorf("failed to find neighbours: %w", err)
+		}
+
+		var prev, next string
+		if position == 1 {
+			if len(neighbours) > 0 {
+				next = neighbours[0]
+			}
+		} else {
+			prev = neighbours[0]
+			if len(neighbours) > 1 {
+				next = neighbours[1]
+			}
+		}
+
+		if !slices.Contains(neighbours, "") {
+			if current != "" && current > prev && (next == "" || current < next) {
+				return current, nil
+			}
+			if rank, err := rankBetween(prev, next); err == nil {
+				return rank, nil
+			}
+		}
+		if attempt > 0 {
+			return "", errRankOrder
+		}
+		if err := rebalanceRanks(tx, model, parentColumn, parentID, rankOrder); err != nil {
+			return "", err
+		}
+	}
+}
+
+// rebalanceRanks gives the rows under a parent evenly spaced ranks in the
+// given order. Rows in the trash keep their place too, so they come back
+// where they were when restored.
+func rebalanceRanks(tx *gorm.DB, model any, parentColumn, parentID, order string) error {
+	var ids []string
+	if err := tx.Unscoped().Model(model).Where(parentColumn+" = ?", parentID).Order(order).Pluck("id", &ids).Error; err != nil {
+		return fmt.Errorf("failed to load ranks: %w", err)
+	}
+
+	for i, rank := range rankSequence(len(ids)) {
+		if err := tx.Unscoped().Model(model).Where("id = ?", ids[i]).UpdateColumn("rank", rank).Error; err != nil {
+			return fmt.Errorf("failed to update rank: %w", err)
+		}
+	}
+	return nil
+}
+
+type RankService struct{}
+
+func NewRankService() *RankService {
+	return &RankService{}
+}
+
+// MigratePositions ranks lists and cards stored before ordering moved to
+// ranks, keeping their old integer position order, and then drops the
+// position columns. It does nothing once the columns are gone.
+func (s *RankService) MigratePositions() error {
+	migrator := database.DB.Migrator()
+	for _, target := range rankTargets {
+		if !migrator.HasColumn(target.model, "position") {
+			continue
+		}
+
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			var parentIDs []string
+			if err := tx.Unscoped().Model(target.model).Distinct(target.parentColumn).Pluck(target.parentColumn, &parentIDs).Error; err != nil {
+				return fmt.Errorf("failed to find ranked rows: %w", err)
+			}
+			for _, parentID := range parentIDs {
+				if err := rebalanceRanks(tx, target.model, target.parentColumn, parentID, "position ASC, id ASC"); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to migrate positions to ranks: %w", err)
+		}
+
+		// SQLite drops a column by rebuilding the table, which loses its
+		// indexes, so migrate the model again to put them back
+		if err := migrator.DropColumn(target.model, "position"); err != nil {
+			return fmt.Errorf("failed to drop position column: %w", err)
+		}
+		if err := migrator.AutoMigrate(target.model); err != nil {
+			return fmt.Errorf("failed to restore indexes: %w", err)
+		}
+		log.Printf("Migrated positions to ranks under %s\n", target.parentColumn)
+	}
+	return nil
+}
+
+// Rebalance respaces the ranks under every board or list whose ranks have
+// grown too long or collided, and returns how many it respaced.
+func (s *RankService) Rebalance() (int, error) {
+	rebalanced := 0
+	for _, target := range rankTargets {
+		var parentIDs []string
+		err := database.DB.Unscoped().Model(target.model).
+			Group(target.parentColumn).
+			Having("MAX(LENGTH(rank)) > ? OR MIN(rank) = '' OR COUNT(*) > COUNT(DISTINCT rank)", maxRankLength).
+			Pluck(target.parentColumn, &parentIDs).Error
+		if err != nil {
+			return rebalanced, fmt.Errorf("failed to find ranks to rebalance: %w", err)
+		}
+
+		for _, parentID := range parentIDs {
+			err := database.DB.Transaction(func(tx *gorm.DB) error {
+				return rebalanceRanks(tx, target.model, target.parentColumn, parentID, rankOrder)
+			})
+			if err != nil {
+				return rebalanced, fmt.Errorf("failed to rebalance ranks: %w", err)
+			}
+			rebalanced++
+		}
+	}
+	return rebalanced, nil
+}
+
+// StartRebalanceJob runs Rebalance in the background every interval.
+func (s *RankService) StartRebalanceJob(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			rebalanced, err := s.Rebalance()
+			if err != nil {
+				log.Printf("Rank rebalancing failed: %v\n", err)
+			} else if rebalanced > 0 {
+				log.Printf("Ranks rebalanced under %d boards and lists\n", rebalanced)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// checkRankBetween fails the test unless rank sorts strictly between prev and
+// next and leaves room below it.
+func checkRankBetween(t *testing.T, prev, next, rank string) {
+	t.Helper()
+	if rank <= prev || (next != "" && rank >= next) {
+		t.Errorf("rank %q is not between %q and %q", rank, prev, next)
+	}
+	if rank == "" || strings.HasSuffix(rank, rankDigits[:1]) {
+		t.Errorf("rank %q between %q and %q is empty or ends in %q", rank, prev, next, rankDigits[:1])
+	}
+}
+
+func TestRankMidpoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, next string
+		want       string
+	}{
+		{"empty order", "", "", "i"},
+		{"after last", "i", "", "r"},
+		{"before first", "", "i", "9"},
+		{"split digits", "a", "c", "b"},
+		{"adjacent digits", "a", "b", "ai"},
+		{"adjacent digits with longer next", "a", "b1", "b"},
+		{"after last digit", "z", "", "zi"},
+		{"before lowest digit", "", "1", "0i"},
+		{"shared prefix", "ab", "ad", "ac"},
+		{"shared prefix with adjacent digits", "ab", "ac", "abi"},
+		{"prev is prefix of next", "a", "a5", "a2"},
+		{"shorter prev", "a", "ab", "a5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rankMidpoint(tt.prev, tt.next)
+			if got != tt.want {
+				t.Errorf("rankMidpoint(%q, %q) = %q, want %q", tt.prev, tt.next, got, tt.want)
+			}
+			checkRankBetween(t, tt.prev, tt.next, got)
+		})
+	}
+}
+
+func TestRankBetweenOutOfOrder(t *testing.T) {
+	tests := []struct{ prev, next string }{
+		{"b", "a"},
+		{"b", "b"},
+		{"ab", "a"},
+	}
+	for _, tt := range tests {
+		if _, err := rankBetween(tt.prev, tt.next); !errors.Is(err, errRankOrder) {
+			t.Errorf("rankBetween(%q, %q) error = %v, want %v", tt.prev, tt.next, err, errRankOrder)
+		}
+	}
+}
+
+func TestRankRepeatedInserts(t *testing.T) {
+	tests := []struct {
+		name   string
+		insert func(first, last string) (prev, next string)
+	}{
+		{"at the front", func(first, last string) (string, string) { return "", first }},
+		{"at the end", func(first, last string) (string, string) { return last, "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, err := rankBetween("", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			last := first
+			for range 500 {
+				prev, next := tt.insert(first, last)
+				rank, err := rankBetween(prev, next)
+				if err != nil {
+					t.Fatalf("rankBetween(%q, %q): %v", prev, next, err)
+				}
+				checkRankBetween(t, prev, next, rank)
+				if prev == "" {
+					first = rank
+				} else {
+					last = rank
+				}
+			}
+		})
+	}
+}
+
+func TestRankRepeatedInsertsInSameGap(t *testing.T) {
+	prev, next := "a", "b"
+	for i := range 200 {
+		rank, err := rankBetween(prev, next)
+		if err != nil {
+			t.Fatalf("insert %d: rankBetween(%q, %q): %v", i, prev, next, err)
+		}
+		checkRankBetween(t, prev, next, rank)
+		// Alternate sides so the gap keeps narrowing from both ends
+		if i%2 == 0 {
+			prev = rank
+		} else {
+			next = rank
+		}
+	}
+}
+
+func TestRankSequence(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 35, 36, 100, 1000, 10000} {
+		ranks := rankSequence(n)
+		if len(ranks) != n {
+			t.Errorf("rankSequence(%d) returned %d ranks", n, len(ranks))
+			continue
+		}
+		prev := ""
+		for i, rank := range ranks {
+			checkRankBetween(t, prev, "", rank)
+			if len(rank) > maxRankLength {
+				t.Errorf("rankSequence(%d)[%d] = %q is longer than %d", n, i, rank, maxRankLength)
+			}
+			prev = rank
+		}
+	}
+
+	if got := rankSequence(1); got[0] != "i" {
+		t.Errorf("rankSequence(1) = %q, want [\"i\"]", got)
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// useTestDB connects the database to a fresh file, set up with the same
+// schema, migrations and Casbin roles as at startup. The test runs in a
+// temporary directory holding the Casbin model, as the server does.
+func useTestDB(t *testing.T) {
+	t.Helper()
+	conf, err := os.ReadFile(filepath.Join("..", "auth", "casbin_model.conf"))
+	if err != nil {
+		t.Fatalf("failed to read casbin model: %v", err)
+	}
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "auth"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "auth", "casbin_model.conf"), conf, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	database.ConnectDatabase()
+	t.Cleanup(func() {
+		if sqlDB, err := database.DB.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+}
+
+// createTestUser adds a user who can be granted roles.
+func createTestUser(t *testing.T) string {
+	t.Helper()
+	id := uuid.New().String()
+	user := models.User{ID: id, Username: "user-" + id, Email: id + "@example.com", Password: "-", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return id
+}
+
+// testBoard is a board in its own organization and project, owned by OwnerID.
+type testBoard struct {
+	OwnerID   string
+	OrgID     string
+	ProjectID string
+	BoardID   string
+}
+
+// newTestBoard sets up a fresh database holding one board.
+func newTestBoard(t *testing.T) testBoard {
+	t.Helper()
+	useTestDB(t)
+
+	b := testBoard{OwnerID: createTestUser(t)}
+	org, err := NewOrganizationService().CreateOrganization("Test Org", b.OwnerID)
+	if err != nil {
+		t.Fatalf("CreateOrganization: %v", err)
+	}
+	project, err := NewProjectService().CreateProject(org.ID, "Test Project", "", b.OwnerID)
+	if err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	b.OrgID, b.ProjectID = org.ID, project.ID
+	return newTestBoardIn(t, b)
+}
+
+// newTestBoardIn adds another board to the project of b.
+func newTestBoardIn(t *testing.T, b testBoard) testBoard {
+	t.Helper()
+	board, err := NewBoardService().CreateBoard(b.ProjectID, "Board "+uuid.New().String()[:8], "", b.OwnerID)
+	if err != nil {
+		t.Fatalf("CreateBoard: %v", err)
+	}
+	b.BoardID = board.ID
+	return b
+}
+
+// addTestList adds a list at the end of the board.
+func addTestList(t *testing.T, b testBoard, name string) string {
+	t.Helper()
+	list, err := NewListService().CreateList(b.BoardID, name, nil, "", b.OwnerID)
+	if err != nil {
+		t.Fatalf("CreateList: %v", err)
+	}
+	return list.ID
+}
+
+// addTestCard adds a card at the end of the list.
+func addTestCard(t *testing.T, b testBoard, listID, title string) string {
+	t.Helper()
+	card, err := NewCardService().CreateCard(listID, title, "", nil, b.OwnerID)
+	if err != nil {
+		t.Fatalf("CreateCard: %v", err)
+	}
+	return card.ID
+}
+
+// loadTestCard reads a card as stored, including one in the trash.
+func loadTestCard(t *testing.T, cardID string) models.Card {
+	t.Helper()
+	var card models.Card
+	if err := database.DB.Unscoped().First(&card, "id = ?", cardID).Error; err != nil {
+		t.Fatalf("failed to load card: %v", err)
+	}
+	return card
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TrashService struct {
+	boardService *BoardService
+	cardService  *CardService
+}
+
+func NewTrashService() *TrashService {
+	return &TrashService{
+		boardService: NewBoardService(),
+		cardService:  NewCardService(),
+	}
+}
+
+func (s *TrashService) GetProjectTrash(projectID string) (*models.Trash, error) {
+	trash := models.Trash{Boards: []models.Board{}, Lists: []models.List{}, Cards: []models.Card{}}
+	result := database.DB.Unscoped().Where("project_id = ? AND deleted_at IS NOT NULL", projectID).Order("deleted_at DESC").Find(&trash.Boards)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve trashed boards: %w", result.Error)
+	}
+	return &trash, nil
+}
+
+func (s *TrashService) GetBoardTrash(boardID string) (*models.Trash, error) {
+	trash := models.Trash{Boards: []models.Board{}, Lists: []models.List{}, Cards: []models.Card{}}
+	result := database.DB.Unscoped().Where("board_id = ? AND deleted_at IS NOT NULL", boardID).Order("deleted_at DESC").Find(&trash.Lists)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve trashed lists: %w", result.Error)
+	}
+
+	boardLists := database.DB.Unscoped().Model(&models.List{}).Select("id").Where("board_id = ?", boardID)
+	result = database.DB.Unscoped().Where("list_id IN (?) AND deleted_at IS NOT NULL", boardLists).Order("deleted_at DESC").Find(&trash.Cards)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve trashed cards: %w", result.Error)
+	}
+	return &trash, nil
+}
+
+func (s *TrashService) RestoreBoard(boardID string) (*models.Board, error) {
+	result := database.DB.Unscoped().Model(&models.Board{}).
+		Where("id = ? AND deleted_at IS NOT NULL", boardID).
+		Updates(map[string]any{"deleted_at": nil, "updated_at": time.Now()})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to restore board: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("board not found in trash")
+	}
+
+	log.Printf("Board restored: ID %s\n", boardID)
+	return s.boardService.GetBoardByID(boardID)
+}
+
+// RestoreList puts the list back under its previous rank, between the lists
+// that were its neighbours or whatever has taken their place. Its cards come
+// back without a WIP limit check: no card can enter a list in the trash, so
+// it returns holding the cards it held when deleted.
+func (s *TrashService) RestoreList(listID, actorID string) (*models.List, error) {
+	tx := database.DB.Begin()
+	if tx.Error != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", tx.Error)
+	}
+
+	var list models.List
+	if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&list, "id = ?", listID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("list not found in trash")
+		}
+		return nil, fmt.Errorf("failed to find list to restore: %w", err)
+	}
+
+	var boards int64
+	if err := tx.Model(&models.Board{}).Where("id = ?", list.BoardID).Count(&boards).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to check parent board: %w", err)
+	}
+	if boards == 0 {
+		tx.Rollback()
+		return nil, errors.New("parent board is in the trash; restore it first")
+	}
+
+	if err := tx.Unscoped().Model(&list).Updates(map[string]any{"deleted_at": nil, "updated_at": time.Now()}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to restore list: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	log.Printf("List restored: ID %s at rank %s\n", listID, list.Rank)
+
+	// The list comes back with its cards, so send them along for clients
+	// to put back on the board
+	restored := models.List{Cards: []*models.Card{}}
+	err := database.DB.Preload("Cards", func(db *gorm.DB) *gorm.DB {
+		return db.Order("cards.rank ASC, cards.id ASC")
+	}).First(&restored, "id = ?", listID).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload restored list: %w", err)
+	}
+	publishBoardEvent(restored.BoardID, models.EventListRestored, actorID, restored)
+	return &restored, nil
+}
+
+// RestoreCard puts the card back under its previous rank, between the cards
+// that were its neighbours or whatever has taken their place.
+func (s *TrashService) RestoreCard(cardID, actorID string) (*models.Card, error) {
+	tx := database.DB.Begin()
+	if tx.Error != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", tx.Error)
+	}
+
+	var card models.Card
+	if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&card, "id = ?", cardID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("card not found in trash")
+		}
+		return nil, fmt.Errorf("failed to find card to restore: %w", err)
+	}
+
+	var lists int64
+	if err := tx.Model(&models.List{}).Where("id = ?", card.ListID).Count(&lists).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to check parent list: %w", err)
+	}
+	if lists == 0 {
+		tx.Rollback()
+		return nil, errors.New("parent list is in the trash; restore it first")
+	}
+
+	// The card is checked as if entering the list: it must be open, and cards
+	// in the trash don't count towards the WIP limit
+	if err := checkListOpen(tx, card.ListID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	exceeded, err := checkWIPLimit(tx, card.ListID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Unscoped().Model(&card).Updates(map[string]any{"deleted_at": nil, "updated_at": time.Now()}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to restore card: %w", err)
+	}
+
+	if err := recordActivity(tx, actorID, &card, models.ActivityCardRestored, nil, cardPlacement(&card)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	log.Printf("Card restored: ID %s at rank %s\n", cardID, card.Rank)
+	restored, err := s.cardService.reloadAndPublish(cardID, models.EventCardRestored, actorID)
+	if err != nil {
+		return nil, err
+	}
+	restored.WIPLimitExceeded = exceeded
+	return restored, nil
+}
+
+// PurgeExpired permanently deletes boards, lists and cards that have been in
+// the trash for longer than retention, along with everything beneath them.
+func (s *TrashService) PurgeExpired(retention time.Duration) (*models.DeletionSummary, error) {
+	cutoff := time.Now().Add(-retention)
+
+	var deleter *cascadeDeleter
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		deleter = newCascadeDeleter(tx)
+
+		var boardIDs []string
+		if err := tx.Unscoped().Model(&models.Board{}).Where("deleted_at < ?", cutoff).Pluck("id", &boardIDs).Error; err != nil {
+			return fmt.Errorf("failed to find expired boards: %w", err)
+		}
+		if err := deleter.deleteBoards(boardIDs); err != nil {
+			return err
+		}
+
+		var listIDs []string
+		if err := tx.Unscoped().Model(&models.List{}).Where("deleted_at < ?", cutoff).Pluck("id", &listIDs).Error; err != nil {
+			return fmt.Errorf("failed to find expired lists: %w", err)
+		}
+		if err := deleter.deleteLists(listIDs); err != nil {
+			return err
+		}
+
+		var cardIDs []string
+		if err := tx.Unscoped().Model(&models.Card{}).Where("deleted_at < ?", cutoff).Pluck("id", &cardIDs).Error; err != nil {
+			return fmt.Errorf("failed to find expired cards: %w", err)
+		}
+		return deleter.deleteCards(cardIDs)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	if err := deleter.removePolicies(); err != nil {
+		return nil, err
+	}
+	return &deleter.summary, nil
+}
+
+// StartPurgeJob runs PurgeExpired in the background every interval.
+func (s *TrashService) StartPurgeJob(interval, retention time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			summary, err := s.PurgeExpired(retention)
+			if err != nil {
+				log.Printf("Trash purge failed: %v\n", err)
+			} else if summary.Boards+summary.Lists+summary.Cards > 0 {
+				log.Printf("Trash purged: %d boards, %d lists, %d cards\n", summary.Boards, summary.Lists, summary.Cards)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// AnyVersion makes an update or delete apply to whatever version of the
+// resource is current, as requested with "If-Match: *".
+const AnyVersion = 0
+
+var errVersionMismatch = errors.New("version mismatch: the resource has been changed since it was read")
+
+// claimVersion moves a loaded row from the expected version to the next one,
+// failing if the row is at any other version. It must run in the transaction
+// that writes the change, so of several writers holding the same version only
+// the first succeeds. On success the row's version field is advanced too, so
+// saving the row afterwards keeps the new version.
+func claimVersion(tx *gorm.DB, row any, version *int, expected int) error {
+	if expected == AnyVersion {
+		expected = *version
+	}
+	if *version != expected {
+		return errVersionMismatch
+	}
+
+	result := tx.Model(row).Where("version = ?", expected).UpdateColumn("version", gorm.Expr("version + 1"))
+	if result.Error != nil {
+		return fmt.Errorf("failed to update version: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errVersionMismatch
+	}
+	*version = expected + 1
+	return nil
+}
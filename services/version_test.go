@@ -0,0 +1,203 @@
+package services
+
+import (
+	"errors"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"testing"
+)
+
+func TestClaimVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected int
+		// changed has another writer claim the row between loading and
+		// claiming it
+		changed bool
+		wantErr error
+	}{
+		{"current version", 1, false, nil},
+		{"any version", AnyVersion, false, nil},
+		{"stale version", 2, false, errVersionMismatch},
+		{"changed since loaded", 1, true, errVersionMismatch},
+		{"any version changed since loaded", AnyVersion, true, errVersionMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBoard(t)
+			var board models.Board
+			if err := database.DB.First(&board, "id = ?", b.BoardID).Error; err != nil {
+				t.Fatal(err)
+			}
+			if tt.changed {
+				other := board
+				if err := claimVersion(database.DB, &other, &other.Version, AnyVersion); err != nil {
+					t.Fatalf("concurrent claim: %v", err)
+				}
+			}
+
+			err := claimVersion(database.DB, &board, &board.Version, tt.expected)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("claimVersion error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if board.Version != 2 {
+				t.Errorf("version = %d, want 2", board.Version)
+			}
+			var stored models.Board
+			database.DB.First(&stored, "id = ?", b.BoardID)
+			if stored.Version != 2 {
+				t.Errorf("stored version = %d, want 2", stored.Version)
+			}
+		})
+	}
+}
+
+func TestUpdateAndDeleteRequireCurrentVersion(t *testing.T) {
+	b := newTestBoard(t)
+	boards := NewBoardService()
+
+	updated, err := boards.UpdateBoard(b.BoardID, 1, models.UpdateBoardRequest{Name: "Renamed Board"})
+	if err != nil {
+		t.Fatalf("UpdateBoard with the current version: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("version after update = %d, want 2", updated.Version)
+	}
+
+	// A second writer still holding version 1 is refused, and the board keeps
+	// the first writer's change
+	if _, err := boards.UpdateBoard(b.BoardID, 1, models.UpdateBoardRequest{Name: "Clobbered Board"}); !errors.Is(err, errVersionMismatch) {
+		t.Errorf("UpdateBoard with a stale version error = %v, want %v", err, errVersionMismatch)
+	}
+	if _, err := boards.DeleteBoard(b.BoardID, 1); !errors.Is(err, errVersionMismatch) {
+		t.Errorf("DeleteBoard with a stale version error = %v, want %v", err, errVersionMismatch)
+	}
+	board, err := boards.GetBoardByID(b.BoardID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.Name != "Renamed Board" || board.Version != 2 {
+		t.Errorf("board = %q at version %d, want %q at version 2", board.Name, board.Version, "Renamed Board")
+	}
+
+	if _, err := boards.DeleteBoard(b.BoardID, AnyVersion); err != nil {
+		t.Errorf("DeleteBoard with any version: %v", err)
+	}
+}
+
+func TestCardRelationsRequireCurrentVersion(t *testing.T) {
+	// Each change runs against a card at version 4, holding the label and
+	// with its owner assigned and watching
+	changes := []struct {
+		name   string
+		change func(cards *CardService, b testBoard, cardID, labelID string, version int) (*models.Card, error)
+	}{
+		{"set labels", func(cards *CardService, b testBoard, cardID, labelID string, version int) (*models.Card, error) {
+			return cards.SetCardLabels(cardID, version, nil, b.OwnerID)
+		}},
+		{"remove label", func(cards *CardService, b testBoard, cardID, labelID string, version int) (*models.Card, error) {
+			return cards.RemoveLabelFromCard(cardID, version, labelID, b.OwnerID)
+		}},
+		{"remove assignee", func(cards *CardService, b testBoard, cardID, labelID string, version int) (*models.Card, error) {
+			return cards.RemoveAssignee(cardID, version, b.OwnerID, b.OwnerID)
+		}},
+		{"remove watcher", func(cards *CardService, b testBoard, cardID, labelID string, version int) (*models.Card, error) {
+			return cards.RemoveWatcher(cardID, version, b.OwnerID, b.OwnerID)
+		}},
+	}
+	for _, tc := range changes {
+		t.Run(tc.name, func(t *testing.T) {
+			b := newTestBoard(t)
+			cardID := addTestCard(t, b, addTestList(t, b, "To Do"), "Task")
+			label, err := NewLabelService().CreateLabel(b.OrgID, b.BoardID, "Bug", "#ff0000", b.OwnerID)
+			if err != nil {
+				t.Fatalf("CreateLabel: %v", err)
+			}
+			cards := NewCardService()
+			if _, err := cards.AddLabelToCard(cardID, 1, label.ID, b.OwnerID); err != nil {
+				t.Fatalf("AddLabelToCard: %v", err)
+			}
+			if _, err := cards.AddAssignee(cardID, 1, b.OwnerID, b.OwnerID); !errors.Is(err, errVersionMismatch) {
+				t.Fatalf("AddAssignee with a stale version error = %v, want %v", err, errVersionMismatch)
+			}
+			if _, err := cards.AddAssignee(cardID, AnyVersion, b.OwnerID, b.OwnerID); err != nil {
+				t.Fatalf("AddAssignee: %v", err)
+			}
+			if _, err := cards.AddWatcher(cardID, 3, b.OwnerID, b.OwnerID); err != nil {
+				t.Fatalf("AddWatcher: %v", err)
+			}
+
+			if _, err := tc.change(cards, b, cardID, label.ID, 3); !errors.Is(err, errVersionMismatch) {
+				t.Fatalf("error with a stale version = %v, want %v", err, errVersionMismatch)
+			}
+			card, err := tc.change(cards, b, cardID, label.ID, 4)
+			if err != nil {
+				t.Fatalf("error with the current version = %v", err)
+			}
+			if card.Version != 5 {
+				t.Errorf("version = %d, want 5", card.Version)
+			}
+		})
+	}
+}
+
+func TestCommentAndAttachmentDeletesRequireCurrentVersion(t *testing.T) {
+	deletes := []struct {
+		name   string
+		create func(b testBoard, cardID string) (id string, version int, err error)
+		delete func(b testBoard, id string, version int) error
+		table  string
+	}{
+		{"comment", func(b testBoard, cardID string) (string, int, error) {
+			comment, err := NewCommentService().CreateComment(cardID, b.OwnerID, "Looks good")
+			if err != nil {
+				return "", 0, err
+			}
+			return comment.ID, comment.Version, nil
+		}, func(b testBoard, id string, version int) error {
+			return NewCommentService().DeleteComment(id, version, b.OwnerID)
+		}, "comments"},
+		{"attachment", func(b testBoard, cardID string) (string, int, error) {
+			attachment, err := NewAttachmentService().CreateAttachment(cardID, "spec.pdf", "https://example.com/spec.pdf", "application/pdf", b.OwnerID)
+			if err != nil {
+				return "", 0, err
+			}
+			return attachment.ID, attachment.Version, nil
+		}, func(b testBoard, id string, version int) error {
+			return NewAttachmentService().DeleteAttachment(id, version, b.OwnerID)
+		}, "attachments"},
+	}
+	for _, tc := range deletes {
+		t.Run(tc.name, func(t *testing.T) {
+			b := newTestBoard(t)
+			cardID := addTestCard(t, b, addTestList(t, b, "To Do"), "Task")
+			id, version, err := tc.create(b, cardID)
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			if version != 1 {
+				t.Fatalf("version after create = %d, want 1", version)
+			}
+
+			if err := tc.delete(b, id, 2); !errors.Is(err, errVersionMismatch) {
+				t.Errorf("delete with a stale version error = %v, want %v", err, errVersionMismatch)
+			}
+			var count int64
+			database.DB.Table(tc.table).Where("id = ?", id).Count(&count)
+			if count != 1 {
+				t.Fatalf("%s deleted by a refused request", tc.name)
+			}
+
+			if err := tc.delete(b, id, 1); err != nil {
+				t.Fatalf("delete with the current version: %v", err)
+			}
+			database.DB.Table(tc.table).Where("id = ?", id).Count(&count)
+			if count != 0 {
+				t.Errorf("%s not deleted", tc.name)
+			}
+		})
+	}
+}
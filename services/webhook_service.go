@@ -0,0 +1,448 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kanban-app/api/auth"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Deliveries are retried after webhookRetryDelay, then twice as long after
+// every further failure, until webhookMaxAttempts attempts have been made.
+const (
+	webhookMaxAttempts  = 8
+	webhookRetryDelay   = 30 * time.Second
+	webhookTimeout      = 10 * time.Second
+	webhookDeliverBatch = 50
+)
+
+// Headers sent with every delivery. The signature is the hex HMAC-SHA256 of
+// the timestamp, a dot and the body, keyed with the webhook's secret.
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+type WebhookService struct {
+	client           *http.Client
+	hierarchyService *HierarchyService
+	wake             chan struct{}
+}
+
+func NewWebhookService() *WebhookService {
+	return &WebhookService{
+		client:           &http.Client{Timeout: webhookTimeout},
+		hierarchyService: NewHierarchyService(),
+		wake:             make(chan struct{}, 1),
+	}
+}
+
+// SignWebhookPayload returns the value of the signature header for a body
+// sent at the given Unix timestamp.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateWebhook registers a webhook for the whole organization, or for a
+// single board of it when boardID is set.
+func (s *WebhookService) CreateWebhook(orgID, boardID, actorID string, req models.CreateWebhookRequest) (*models.CreatedWebhook, error) {
+	if err := validateEventFilter(req.Events); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		secret = hex.EncodeToString(key)
+	}
+
+	webhook := models.Webhook{
+		ID:             uuid.New().String(),
+		OrganizationID: orgID,
+		BoardID:        boardID,
+		URL:            req.URL,
+		Secret:         secret,
+		Events:         req.Events,
+		Active:         true,
+		CreatedBy:      actorID,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+
+	if err := database.DB.Create(&webhook).Error; err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	if _, err := auth.NewAuthorizationService().AddResourceParent(webhook.ID, webhook.ScopeID()); err != nil {
+		return nil, fmt.Errorf("failed to link new webhook to its scope: %w", err)
+	}
+
+	log.Printf("Webhook created: %s for scope %s by user %s\n", webhook.ID, webhook.ScopeID(), actorID)
+	return &models.CreatedWebhook{Webhook: webhook, Secret: secret}, nil
+}
+
+func (s *WebhookService) GetOrganizationWebhooks(orgID string) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	result := database.DB.Where("organization_id = ? AND board_id = ''", orgID).Order("created_at ASC").Find(&webhooks)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve webhooks: %w", result.Error)
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookService) GetBoardWebhooks(boardID string) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	result := database.DB.Where("board_id = ?", boardID).Order("created_at ASC").Find(&webhooks)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve webhooks: %w", result.Error)
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookService) GetWebhookByID(webhookID string) (*models.Webhook, error) {
+	var webhook models.Webhook
+	result := database.DB.First(&webhook, "id = ?", webhookID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook not found")
+		}
+		return nil, fmt.Errorf("failed to retrieve webhook: %w", result.Error)
+	}
+	return &webhook, nil
+}
+
+func (s *WebhookService) UpdateWebhook(webhookID string, version int, updateReq models.UpdateWebhookRequest) (*models.Webhook, error) {
+	webhook, err := s.GetWebhookByID(webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The changed fields are written from the struct, as only that applies
+	// the JSON serializer of the event filter
+	fields := []string{}
+	if updateReq.URL != nil {
+		webhook.URL = *updateReq.URL
+		fields = append(fields, "URL")
+	}
+	if updateReq.Secret != nil {
+		webhook.Secret = *updateReq.Secret
+		fields = append(fields, "Secret")
+	}
+	if updateReq.Events != nil {
+		if err := validateEventFilter(*updateReq.Events); err != nil {
+			return nil, err
+		}
+		webhook.Events = *updateReq.Events
+		fields = append(fields, "Events")
+	}
+	if updateReq.Active != nil {
+		webhook.Active = *updateReq.Active
+		fields = append(fields, "Active")
+	}
+	webhook.UpdatedAt = time.Now()
+	fields = append(fields, "UpdatedAt")
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, webhook, &webhook.Version, version); err != nil {
+			return err
+		}
+		return tx.Model(webhook).Select(fields).UpdateColumns(webhook).Error
+	})
+	if err != nil {
+		if errors.Is(err, errVersionMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook removes the webhook together with its delivery log.
+func (s *WebhookService) DeleteWebhook(webhookID string, version int) error {
+	var deleter *cascadeDeleter
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var webhook models.Webhook
+		if err := tx.First(&webhook, "id = ?", webhookID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("webhook not found or already deleted")
+			}
+			return err
+		}
+		if err := claimVersion(tx, &webhook, &webhook.Version, version); err != nil {
+			return err
+		}
+		deleter = newCascadeDeleter(tx)
+		if err := deleter.deleteWebhooks([]string{webhookID}); err != nil {
+			return err
+		}
+		if deleter.summary.Webhooks == 0 {
+			return errors.New("webhook not found or already deleted")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := deleter.removePolicies(); err != nil {
+		return err
+	}
+
+	log.Printf("Webhook deleted: ID %s\n", webhookID)
+	return nil
+}
+
+// GetDeliveries returns a page of a webhook's delivery log, newest first.
+func (s *WebhookService) GetDeliveries(webhookID string, query models.PaginationQuery) (*models.WebhookDeliveryPage, error) {
+	query = query.Normalize()
+	page := models.WebhookDeliveryPage{Deliveries: []models.WebhookDelivery{}, Page: query.Page, PerPage: query.PerPage}
+
+	if err := database.DB.Model(&models.WebhookDelivery{}).Where("webhook_id = ?", webhookID).Count(&page.Total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count deliveries: %w", err)
+	}
+
+	result := database.DB.Where("webhook_id = ?", webhookID).
+		Order("created_at DESC, id DESC").
+		Limit(query.PerPage).Offset(query.Offset()).
+		Find(&page.Deliveries)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to retrieve deliveries: %w", result.Error)
+	}
+	return &page, nil
+}
+
+// EnqueueEvent queues a delivery of the event for every active webhook of
+// the event's board or its organization that subscribes to the event type.
+func (s *WebhookService) EnqueueEvent(event models.BoardEvent) error {
+	path, err := s.hierarchyService.ResolvePath(models.ResourcePath{BoardID: event.BoardID})
+	if err != nil {
+		return err
+	}
+
+	var webhooks []models.Webhook
+	result := database.DB.Where("active AND organization_id = ? AND board_id IN ?", path.OrganizationID, []string{"", event.BoardID}).Find(&webhooks)
+	if result.Error != nil {
+		return fmt.Errorf("failed to find webhooks: %w", result.Error)
+	}
+
+	var deliveries []models.WebhookDelivery
+	var payload []byte
+	for _, webhook := range webhooks {
+		if !matchesEventFilter(webhook.Events, event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return fmt.Errorf("failed to encode event: %w", err)
+			}
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			ID:            uuid.New().String(),
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       string(payload),
+			Status:        models.DeliveryStatusPending,
+			NextAttemptAt: time.Now(),
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := database.DB.Create(&deliveries).Error; err != nil {
+		return fmt.Errorf("failed to queue deliveries: %w", err)
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// DeliverDue attempts every pending delivery whose next attempt is due and
+// returns how many were attempted.
+func (s *WebhookService) DeliverDue() (int, error) {
+	attempted := 0
+	for {
+		var deliveries []models.WebhookDelivery
+		result := database.DB.Where("status = ? AND next_attempt_at <= ?", models.DeliveryStatusPending, time.Now()).
+			Order("next_attempt_at ASC").
+			Limit(webhookDeliverBatch).
+			Find(&deliveries)
+		if result.Error != nil {
+			return attempted, fmt.Errorf("failed to find due deliveries: %w", result.Error)
+		}
+		if len(deliveries) == 0 {
+			return attempted, nil
+		}
+
+		for i := range deliveries {
+			if err := s.deliver(&deliveries[i]); err != nil {
+				return attempted, err
+			}
+			attempted++
+		}
+	}
+}
+
+// StartDispatcher queues deliveries for every published board event and
+// sends them from a background goroutine, checking for due retries every
+// interval.
+func (s *WebhookService) StartDispatcher(interval time.Duration) {
+	boardEvents.listen(func(event models.BoardEvent) {
+		if err := s.EnqueueEvent(event); err != nil {
+			log.Printf("Failed to queue webhooks for %s %s: %v\n", event.Type, event.ID, err)
+		}
+	})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if _, err := s.DeliverDue(); err != nil {
+				log.Printf("Webhook delivery failed: %v\n", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-s.wake:
+			}
+		}
+	}()
+}
+
+// deliver makes one attempt at a delivery and records the outcome.
+func (s *WebhookService) deliver(delivery *models.WebhookDelivery) error {
+	var webhook models.Webhook
+	err := database.DB.Limit(1).Find(&webhook, "id = ?", delivery.WebhookID).Error
+	if err != nil {
+		return fmt.Errorf("failed to find webhook: %w", err)
+	}
+
+	now := time.Now()
+	delivery.UpdatedAt = now
+	if webhook.ID == "" || !webhook.Active {
+		delivery.Status = models.DeliveryStatusFailed
+		delivery.LastError = "webhook was deactivated"
+		return database.DB.Save(delivery).Error
+	}
+
+	delivery.Attempts++
+	statusCode, sendErr := s.send(&webhook, delivery)
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = models.DeliveryStatusSucceeded
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= webhookMaxAttempts:
+		delivery.Status = models.DeliveryStatusFailed
+		delivery.LastError = sendErr.Error()
+	default:
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = now.Add(webhookRetryDelay << (delivery.Attempts - 1))
+	}
+
+	if err := database.DB.Save(delivery).Error; err != nil {
+		return fmt.Errorf("failed to record delivery: %w", err)
+	}
+	return nil
+}
+
+// send posts the delivery's payload to the webhook, returning the response
+// status code if there was a response and an error unless it was a 2xx.
+func (s *WebhookService) send(webhook *models.Webhook, delivery *models.WebhookDelivery) (*int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kanban-webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &resp.StatusCode, fmt.Errorf("receiver responded with %s", resp.Status)
+	}
+	return &resp.StatusCode, nil
+}
+
+// matchesEventFilter reports whether an event type is selected by a webhook's
+// filter. "*" selects everything and "card.*" every card event.
+func matchesEventFilter(filter []string, eventType string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, pattern := range filter {
+		if pattern == "*" || pattern == eventType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasSuffix(prefix, ".") && strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateEventFilter(filter []string) error {
+	for _, pattern := range filter {
+		if pattern == "*" {
+			continue
+		}
+		prefix, wildcard := strings.CutSuffix(pattern, "*")
+		known := slices.ContainsFunc(models.BoardEventTypes, func(eventType string) bool {
+			if wildcard {
+				return strings.HasSuffix(prefix, ".") && strings.HasPrefix(eventType, prefix)
+			}
+			return eventType == pattern
+		})
+		if !known {
+			return fmt.Errorf("unknown event type in filter: %s", pattern)
+		}
+	}
+	return nil
+}
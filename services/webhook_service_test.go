@@ -0,0 +1,226 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"kanban-app/api/database"
+	"kanban-app/api/models"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func createTestWebhook(t *testing.T, orgID, url, secret string, events []string) models.Webhook {
+	t.Helper()
+	webhook := models.Webhook{
+		ID:             uuid.New().String(),
+		OrganizationID: orgID,
+		URL:            url,
+		Secret:         secret,
+		Events:         events,
+		Active:         true,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if err := database.DB.Create(&webhook).Error; err != nil {
+		t.Fatalf("failed to create webhook: %v", err)
+	}
+	return webhook
+}
+
+func testEvent(boardID, eventType string) models.BoardEvent {
+	return models.BoardEvent{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		BoardID:   boardID,
+		Data:      map[string]string{"card_id": "card-1"},
+		CreatedAt: time.Now(),
+	}
+}
+
+func deliveriesOf(t *testing.T, webhookID string) []models.WebhookDelivery {
+	t.Helper()
+	var deliveries []models.WebhookDelivery
+	if err := database.DB.Where("webhook_id = ?", webhookID).Find(&deliveries).Error; err != nil {
+		t.Fatalf("failed to load deliveries: %v", err)
+	}
+	return deliveries
+}
+
+func TestWebhookDeliverySignature(t *testing.T) {
+	b := newTestBoard(t)
+	orgID, boardID := b.OrgID, b.BoardID
+
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan received, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	secret := "a-test-secret-of-some-length"
+	webhook := createTestWebhook(t, orgID, server.URL, secret, nil)
+
+	service := NewWebhookService()
+	event := testEvent(boardID, models.EventCardCreated)
+	if err := service.EnqueueEvent(event); err != nil {
+		t.Fatalf("EnqueueEvent: %v", err)
+	}
+	attempted, err := service.DeliverDue()
+	if err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	if attempted != 1 {
+		t.Fatalf("attempted %d deliveries, want 1", attempted)
+	}
+
+	got := <-requests
+	timestamp, err := strconv.ParseInt(got.header.Get(WebhookTimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid %s header: %v", WebhookTimestampHeader, err)
+	}
+	if want := SignWebhookPayload(secret, timestamp, got.body); got.header.Get(WebhookSignatureHeader) != want {
+		t.Errorf("%s = %q, want %q", WebhookSignatureHeader, got.header.Get(WebhookSignatureHeader), want)
+	}
+	if got.header.Get(WebhookEventHeader) != models.EventCardCreated {
+		t.Errorf("%s = %q, want %q", WebhookEventHeader, got.header.Get(WebhookEventHeader), models.EventCardCreated)
+	}
+
+	deliveries := deliveriesOf(t, webhook.ID)
+	if len(deliveries) != 1 {
+		t.Fatalf("got %d deliveries, want 1", len(deliveries))
+	}
+	if got.header.Get(WebhookDeliveryHeader) != deliveries[0].ID {
+		t.Errorf("%s = %q, want %q", WebhookDeliveryHeader, got.header.Get(WebhookDeliveryHeader), deliveries[0].ID)
+	}
+	if deliveries[0].Status != models.DeliveryStatusSucceeded {
+		t.Errorf("status = %q, want %q", deliveries[0].Status, models.DeliveryStatusSucceeded)
+	}
+}
+
+func TestWebhookEventFilter(t *testing.T) {
+	b := newTestBoard(t)
+	orgID, boardID := b.OrgID, b.BoardID
+
+	allEvents := createTestWebhook(t, orgID, "http://127.0.0.1/all", "a-test-secret-of-some-length", nil)
+	cardEvents := createTestWebhook(t, orgID, "http://127.0.0.1/cards", "a-test-secret-of-some-length", []string{"card.*"})
+	listEvents := createTestWebhook(t, orgID, "http://127.0.0.1/lists", "a-test-secret-of-some-length", []string{models.EventListCreated})
+
+	if err := NewWebhookService().EnqueueEvent(testEvent(boardID, models.EventCardMoved)); err != nil {
+		t.Fatalf("EnqueueEvent: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		webhook models.Webhook
+		want    int
+	}{
+		{"no filter", allEvents, 1},
+		{"matching wildcard", cardEvents, 1},
+		{"other event type", listEvents, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(deliveriesOf(t, tt.webhook.ID)); got != tt.want {
+				t.Errorf("got %d deliveries, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	b := newTestBoard(t)
+	webhook := createTestWebhook(t, b.OrgID, "http://127.0.0.1/hook", "a-test-secret-of-some-length", nil)
+	webhooks := NewWebhookService()
+
+	events := []string{"card.*", models.EventListCreated}
+	active := false
+	if _, err := webhooks.UpdateWebhook(webhook.ID, 1, models.UpdateWebhookRequest{Events: &events, Active: &active}); err != nil {
+		t.Fatalf("UpdateWebhook: %v", err)
+	}
+	url := "http://127.0.0.1/clobbered"
+	if _, err := webhooks.UpdateWebhook(webhook.ID, 1, models.UpdateWebhookRequest{URL: &url}); !errors.Is(err, errVersionMismatch) {
+		t.Errorf("UpdateWebhook with a stale version error = %v, want %v", err, errVersionMismatch)
+	}
+
+	var stored models.Webhook
+	if err := database.DB.First(&stored, "id = ?", webhook.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(stored.Events, events) || stored.Active || stored.Version != 2 {
+		t.Errorf("webhook has events %v, active %v at version %d; want %v, false at version 2", stored.Events, stored.Active, stored.Version, events)
+	}
+	if stored.URL != webhook.URL || stored.Secret != webhook.Secret {
+		t.Errorf("fields not in the update changed: url %q, secret %q", stored.URL, stored.Secret)
+	}
+}
+
+func TestMatchesEventFilter(t *testing.T) {
+	tests := []struct {
+		filter    []string
+		eventType string
+		want      bool
+	}{
+		{nil, models.EventCardMoved, true},
+		{[]string{"*"}, models.EventListCreated, true},
+		{[]string{"card.*"}, models.EventCardMoved, true},
+		{[]string{"card.*"}, models.EventListCreated, false},
+		{[]string{models.EventListCreated}, models.EventListCreated, true},
+		{[]string{models.EventListCreated}, models.EventCardMoved, false},
+		{[]string{"card*"}, models.EventCardMoved, false},
+	}
+	for _, tt := range tests {
+		if got := matchesEventFilter(tt.filter, tt.eventType); got != tt.want {
+			t.Errorf("matchesEventFilter(%q, %q) = %v, want %v", tt.filter, tt.eventType, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookDeliveryRetriesServerError(t *testing.T) {
+	b := newTestBoard(t)
+	orgID, boardID := b.OrgID, b.BoardID
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	webhook := createTestWebhook(t, orgID, server.URL, "a-test-secret-of-some-length", nil)
+	service := NewWebhookService()
+	if err := service.EnqueueEvent(testEvent(boardID, models.EventCardCreated)); err != nil {
+		t.Fatalf("EnqueueEvent: %v", err)
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		delivery := deliveriesOf(t, webhook.ID)[0]
+		start := time.Now()
+		if err := service.deliver(&delivery); err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+
+		stored := deliveriesOf(t, webhook.ID)[0]
+		if stored.Status != models.DeliveryStatusPending {
+			t.Errorf("attempt %d: status = %q, want %q", attempt, stored.Status, models.DeliveryStatusPending)
+		}
+		if stored.Attempts != attempt {
+			t.Errorf("attempt %d: attempts = %d, want %d", attempt, stored.Attempts, attempt)
+		}
+		if stored.LastStatusCode == nil || *stored.LastStatusCode != http.StatusServiceUnavailable {
+			t.Errorf("attempt %d: last_status_code = %v, want %d", attempt, stored.LastStatusCode, http.StatusServiceUnavailable)
+		}
+		delay := webhookRetryDelay << (attempt - 1)
+		if stored.NextAttemptAt.Before(start.Add(delay)) || stored.NextAttemptAt.After(time.Now().Add(delay)) {
+			t.Errorf("attempt %d: next_attempt_at = %v, want %v after the attempt", attempt, stored.NextAttemptAt, delay)
+		}
+	}
+}
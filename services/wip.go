@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kanban-app/api/models"
+
+	"gorm.io/gorm"
+)
+
+// errWIPLimitReached refuses a card entering a list that already holds as
+// many cards as its WIP limit. Archived cards and cards in the trash don't
+// count towards the limit.
+var errWIPLimitReached = errors.New("WIP limit reached")
+
+// checkWIPLimit makes sure one more card may enter the list. When the list
+// already holds as many cards as its WIP limit, a board with the strict
+// policy refuses the card and one with the warn policy lets it in, which is
+// reported as exceeded. It must run in the transaction adding the card, so
+// concurrent additions are counted.
+func checkWIPLimit(tx *gorm.DB, listID string) (exceeded bool, err error) {
+	var limit struct {
+		WIPLimit  *int   `gorm:"column:wip_limit"`
+		WIPPolicy string `gorm:"column:wip_policy"`
+	}
+	err = tx.Table("lists").Select("lists.wip_limit, boards.wip_policy").
+		Joins("JOIN boards ON boards.id = lists.board_id").
+		Where("lists.id = ?", listID).
+		Take(&limit).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check WIP limit: %w", err)
+	}
+	if limit.WIPLimit == nil {
+		return false, nil
+	}
+
+	var count int64
+	if err := tx.Model(&models.Card{}).Where("list_id = ? AND archived_at IS NULL", listID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to count cards: %w", err)
+	}
+	if count < int64(*limit.WIPLimit) {
+		return false, nil
+	}
+	if limit.WIPPolicy == models.WIPPolicyWarn {
+		return true, nil
+	}
+	return false, fmt.Errorf("%w: the list already holds %d cards and its limit is %d", errWIPLimitReached, count, *limit.WIPLimit)
+}
+
+// markOverWIPLimit flags the lists holding more unarchived cards than their
+// WIP limit, counting the cards loaded with them.
+func markOverWIPLimit(lists []*models.List) {
+	for _, list := range lists {
+		if list.WIPLimit == nil {
+			continue
+		}
+		count := 0
+		for _, card := range list.Cards {
+			if card.ArchivedAt == nil {
+				count++
+			}
+		}
+		list.OverWIPLimit = count > *list.WIPLimit
+	}
+}
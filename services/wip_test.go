@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"kanban-app/api/models"
+	"testing"
+)
+
+func TestWIPPolicy(t *testing.T) {
+	// Each operation tries to add a second card to a list limited to one
+	operations := []struct {
+		name string
+		add  func(t *testing.T, b testBoard, todo, doing string) (*models.Card, error)
+	}{
+		{"create", func(t *testing.T, b testBoard, todo, doing string) (*models.Card, error) {
+			return NewCardService().CreateCard(doing, "New", "", nil, b.OwnerID)
+		}},
+		{"move", func(t *testing.T, b testBoard, todo, doing string) (*models.Card, error) {
+			card := addTestCard(t, b, todo, "Waiting")
+			result, err := NewCardService().MoveCardToList(card, AnyVersion, b.OwnerID, models.MoveCardRequest{ListID: doing})
+			if err != nil {
+				return nil, err
+			}
+			return result.Card, nil
+		}},
+		{"update", func(t *testing.T, b testBoard, todo, doing string) (*models.Card, error) {
+			card := addTestCard(t, b, todo, "Waiting")
+			return NewCardService().UpdateCard(card, AnyVersion, b.OwnerID, models.UpdateCardRequest{ListID: doing})
+		}},
+		{"unarchive", func(t *testing.T, b testBoard, todo, doing string) (*models.Card, error) {
+			card := addArchivedOverLimit(t, b, doing, func(card string) error {
+				_, err := NewCardService().ArchiveCard(card, AnyVersion, b.OwnerID)
+				return err
+			})
+			return NewCardService().UnarchiveCard(card, AnyVersion, b.OwnerID)
+		}},
+		{"restore", func(t *testing.T, b testBoard, todo, doing string) (*models.Card, error) {
+			card := addArchivedOverLimit(t, b, doing, func(card string) error {
+				_, err := NewCardService().DeleteCard(card, AnyVersion, b.OwnerID)
+				return err
+			})
+			return NewTrashService().RestoreCard(card, b.OwnerID)
+		}},
+	}
+	policies := []struct {
+		policy       string
+		wantErr      error
+		wantExceeded bool
+		wantCards    int64
+	}{
+		{models.WIPPolicyStrict, errWIPLimitReached, false, 1},
+		{models.WIPPolicyWarn, nil, true, 2},
+	}
+
+	for _, op := range operations {
+		for _, p := range policies {
+			t.Run(op.name+"/"+p.policy, func(t *testing.T) {
+				b := newTestBoard(t)
+				if _, err := NewBoardService().UpdateBoard(b.BoardID, AnyVersion, models.UpdateBoardRequest{WIPPolicy: p.policy}); err != nil {
+					t.Fatalf("UpdateBoard: %v", err)
+				}
+				todo := addTestList(t, b, "To Do")
+				limit := 1
+				doing, err := NewListService().CreateList(b.BoardID, "Doing", &limit, "", b.OwnerID)
+				if err != nil {
+					t.Fatalf("CreateList: %v", err)
+				}
+				addTestCard(t, b, doing.ID, "Started")
+
+				card, err := op.add(t, b, todo, doing.ID)
+				if !errors.Is(err, p.wantErr) {
+					t.Fatalf("error = %v, want %v", err, p.wantErr)
+				}
+				if err == nil && card.WIPLimitExceeded != p.wantExceeded {
+					t.Errorf("wip_limit_exceeded = %v, want %v", card.WIPLimitExceeded, p.wantExceeded)
+				}
+
+				metrics, err := NewMetricsService().GetBoardMetrics(b.BoardID, models.MetricsQuery{})
+				if err != nil {
+					t.Fatalf("GetBoardMetrics: %v", err)
+				}
+				for _, list := range metrics.WIP {
+					if list.ListID != doing.ID {
+						continue
+					}
+					if list.Cards != p.wantCards || list.OverWIPLimit != (p.wantCards > 1) {
+						t.Errorf("doing holds %d cards, over limit %v; want %d", list.Cards, list.OverWIPLimit, p.wantCards)
+					}
+				}
+			})
+		}
+	}
+}
+
+// addArchivedOverLimit adds a card to the full list by lifting its limit of
+// one for a moment, then takes the card off it with remove and puts the
+// limit back.
+func addArchivedOverLimit(t *testing.T, b testBoard, listID string, remove func(card string) error) string {
+	t.Helper()
+	setLimit := func(limit models.Optional[int]) {
+		limit.Set = true
+		if _, err := NewListService().UpdateList(listID, AnyVersion, b.OwnerID, models.UpdateListRequest{WIPLimit: limit}); err != nil {
+			t.Fatalf("UpdateList: %v", err)
+		}
+	}
+	setLimit(models.Optional[int]{Null: true})
+	card := addTestCard(t, b, listID, "Parked")
+	if err := remove(card); err != nil {
+		t.Fatalf("failed to take the card off the list: %v", err)
+	}
+	setLimit(models.Optional[int]{Value: 1})
+	return card
+}